@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+
+	"github.com/akyTheDev/currency-bot/internal/bot"
+	"github.com/akyTheDev/currency-bot/internal/config"
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	"github.com/akyTheDev/currency-bot/internal/ratelimit"
+	"github.com/akyTheDev/currency-bot/internal/repository"
+	"github.com/akyTheDev/currency-bot/internal/service"
+	"github.com/akyTheDev/currency-bot/internal/storage"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+type app struct {
+	cfg     *config.Config
+	flags   *config.Flags
+	logger  *log.Logger
+	fetcher *fetcher.Switch
+
+	db             *sql.DB
+	userRepository repository.UserRepository
+	userService    *service.UserService
+	notifyService  *service.NotifyService
+	adminService   *service.AdminService
+	prefService    *service.PreferenceService
+	subService     *service.SubscriptionService
+	updateService  *service.UpdateService
+	digestService  *service.DigestService
+}
+
+func newApp(logger *log.Logger, flags *config.Flags) (*app, error) {
+	cfg, err := config.Load(flags)
+	if err != nil {
+		return nil, err
+	}
+
+	return &app{
+		cfg:     cfg,
+		flags:   flags,
+		logger:  logger,
+		fetcher: fetcher.NewSwitch(newFetcher(cfg.Fetch)),
+	}, nil
+}
+
+// newFetcher builds the provider chain of cfg and registers the codes its
+// extra providers add, so they parse like the ISO ones.
+func newFetcher(cfg config.FetchConfig) *fetcher.Chain {
+	currency.Register(extraCurrencies(cfg)...)
+
+	timeoutSeconds := int(cfg.Timeout.Seconds())
+
+	var providers []fetcher.Provider
+	for _, p := range cfg.Providers {
+		breaker := fetcher.NewBreaker(cfg.Breaker.Failures, cfg.Breaker.Cooldown)
+		transport := fetcher.NewRetryTransport(nil, fetcher.RetryPolicy(cfg.Retry), breaker)
+
+		switch p.Type {
+		case config.ProviderTCMB:
+			providers = append(providers, fetcher.Provider{
+				Name:    p.Name,
+				Fetcher: fetcher.NewTCMBClient(p.URL, timeoutSeconds, cfg.CacheTTL, transport),
+				Breaker: breaker,
+			})
+		case config.ProviderFile:
+			providers = append(providers, fetcher.Provider{Name: p.Name, Fetcher: fetcher.NewFileFetcher(p.Path, p.Poll)})
+		case config.ProviderMetals:
+			providers = append(providers, fetcher.Provider{
+				Name:    p.Name,
+				Fetcher: fetcher.NewMetalsFetcher(p.URL, timeoutSeconds, cfg.CacheTTL, transport),
+				Breaker: breaker,
+				Extra:   true,
+			})
+		case config.ProviderCrypto:
+			providers = append(providers, fetcher.Provider{
+				Name:    p.Name,
+				Fetcher: fetcher.NewCryptoFetcher(p.URL, fetcher.TickerMappers[p.Mapper], cryptoPairs(p), timeoutSeconds, cfg.CacheTTL, transport),
+				Breaker: breaker,
+				Extra:   true,
+			})
+		}
+	}
+
+	return fetcher.NewChain(providers...)
+}
+
+// extraCurrencies are the codes the extra providers add to the bulletin.
+func extraCurrencies(cfg config.FetchConfig) []currency.Code {
+	var codes []currency.Code
+	for _, p := range cfg.Providers {
+		switch p.Type {
+		case config.ProviderMetals:
+			codes = append(codes, fetcher.MetalCodes...)
+		case config.ProviderCrypto:
+			for _, pair := range cryptoPairs(p) {
+				codes = append(codes, pair.Code)
+			}
+		}
+	}
+	return codes
+}
+
+func cryptoPairs(p config.ProviderConfig) []fetcher.CryptoPair {
+	if len(p.Pairs) == 0 {
+		return fetcher.DefaultCryptoPairs
+	}
+	pairs := make([]fetcher.CryptoPair, len(p.Pairs))
+	for i, pair := range p.Pairs {
+		pairs[i] = fetcher.CryptoPair(pair)
+	}
+	return pairs
+}
+
+func (a *app) openDB() error {
+	if a.db != nil {
+		return nil
+	}
+
+	db, err := storage.OpenDB(a.cfg.Database.URL)
+	if err != nil {
+		return fmt.Errorf("couldn't connect to db: %w", err)
+	}
+	db.SetMaxOpenConns(a.cfg.Database.MaxOpenConns)
+	db.SetMaxIdleConns(a.cfg.Database.MaxIdleConns)
+	db.SetConnMaxLifetime(a.cfg.Database.ConnMaxLifetime)
+
+	a.db = db
+	a.userRepository = repository.NewPostgresUserRepository(db)
+	a.userService = service.NewUserService(a.userRepository, a.logger)
+	a.notifyService = service.NewNotifyService(a.logger, a.userRepository, a.fetcher)
+	a.adminService = service.NewAdminService(
+		repository.NewPostgresRoleRepository(db),
+		repository.NewPostgresStatsRepository(db),
+		repository.NewPostgresAuditRepository(db),
+		a.logger,
+	)
+	a.prefService = service.NewPreferenceService(repository.NewPostgresPreferenceRepository(db), a.logger)
+	a.subService = service.NewSubscriptionService(repository.NewPostgresSubscriptionRepository(db), a.logger)
+	a.updateService = service.NewUpdateService(repository.NewPostgresUpdateRepository(db), a.logger)
+	a.digestService = service.NewDigestService(repository.NewPostgresHistoryRepository(db), a.logger)
+	return nil
+}
+
+func (a *app) newBotAPI() (*tgbotapi.BotAPI, error) {
+	botAPI, err := tgbotapi.NewBotAPI(a.cfg.Telegram.Token)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't create a bot: %w", err)
+	}
+	return botAPI, nil
+}
+
+func (a *app) newBotHandler(ctx context.Context) (*bot.BotHandler, error) {
+	if err := a.openDB(); err != nil {
+		return nil, err
+	}
+
+	settings, err := newSettings(a.cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	botAPI, err := a.newBotAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	return bot.NewBotHandler(ctx, botAPI, a.logger, a.userService, a.notifyService, a.adminService, a.prefService, a.subService, a.updateService, a.digestService, a.newLimiter(), settings), nil
+}
+
+func (a *app) newLimiter() ratelimit.Limiter {
+	cfg := ratelimit.Config{Burst: a.cfg.RateLimit.Burst, Refill: a.cfg.RateLimit.Refill}
+	if a.cfg.RateLimit.Backend == config.RateLimitPostgres {
+		return ratelimit.NewPostgres(a.db, cfg)
+	}
+	return ratelimit.NewMemory(cfg)
+}
+
+func newSettings(cfg *config.Config) (*bot.Settings, error) {
+	return bot.NewSettings(bot.SettingsConfig{
+		NotifyInterval: cfg.Schedule.NotifyInterval,
+		DigestHour:     cfg.Schedule.DigestHour,
+		Admins:         cfg.Admins,
+		RateTemplate:   cfg.Templates.Rate,
+		DefaultLocale:  cfg.Locale.Default,
+		Locales:        cfg.Locale.Supported,
+		Currencies:     extraCurrencies(cfg.Fetch),
+	})
+}
+
+func (a *app) close() {
+	if a.db != nil {
+		a.db.Close()
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/models"
+)
+
+func runAudit(ctx context.Context, a *app, args []string) error {
+	var filter models.AuditFilter
+	var since time.Duration
+
+	fs := flag.NewFlagSet("audit", flag.ContinueOnError)
+	fs.Int64Var(&filter.ChatID, "chat", 0, "only show events for this chat id")
+	fs.StringVar(&filter.Type, "type", "", "only show events of this type")
+	fs.DurationVar(&since, "since", 0, "only show events newer than this (e.g. 24h)")
+	fs.IntVar(&filter.Limit, "limit", 50, "maximum number of events")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if since > 0 {
+		filter.Since = time.Now().Add(-since)
+	}
+
+	if err := a.openDB(); err != nil {
+		return err
+	}
+
+	events, err := a.adminService.AuditEvents(filter)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tTYPE\tACTOR\tCHAT_ID\tPAYLOAD")
+	for _, event := range events {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", event.CreatedAt.Format(time.RFC3339), event.Type, event.Actor, event.ChatID, event.Payload)
+	}
+	return w.Flush()
+}
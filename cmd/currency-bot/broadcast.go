@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func runBroadcast(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("broadcast", flag.ContinueOnError)
+	file := fs.String("file", "", "path to a file containing the message text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *file == "" {
+		return errors.New("usage: broadcast --file PATH")
+	}
+
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("read message file: %w", err)
+	}
+
+	text := strings.TrimSpace(string(content))
+	if text == "" {
+		return fmt.Errorf("message file %s is empty", *file)
+	}
+
+	handler, err := a.newBotHandler(ctx)
+	if err != nil {
+		return err
+	}
+
+	sent, err := handler.Broadcast(text)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Broadcast sent to %d users\n", sent)
+	return nil
+}
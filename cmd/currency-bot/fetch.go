@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+)
+
+func runFetch(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("fetch", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "print the bulletin as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	rates, err := a.fetcher.FetchBulletin()
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rates)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "CODE\tUNIT\tBUYING\tSELLING\tNAME\t")
+	for _, rate := range rates {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t\n", rate.Code, rate.Unit, rate.Buying.StringFixed(4), rate.Selling.StringFixed(4), rate.Name)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+)
+
+// serveHTTP runs an HTTP server on addr until ctx is canceled.
+func serveHTTP(ctx context.Context, logger *log.Logger, name, addr string, handler http.Handler) {
+	server := &http.Server{Addr: addr, Handler: handler, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	go func() {
+		logger.Printf("Serving %s on %s\n", name, addr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Printf("%s server: %v\n", name, err)
+		}
+	}()
+}
+
+func metricsHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", expvar.Handler())
+	mux.Handle("/debug/vars", expvar.Handler())
+	return mux
+}
+
+type healthReport struct {
+	Status    string                   `json:"status"`
+	Database  string                   `json:"database"`
+	Providers []fetcher.ProviderHealth `json:"providers"`
+}
+
+// healthHandler reports unhealthy when the database can't be reached or
+// every provider's circuit is open.
+func healthHandler(db *sql.DB, rates *fetcher.Switch) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+		defer cancel()
+
+		report := healthReport{Status: "ok", Database: "ok", Providers: rates.Health()}
+		if err := db.PingContext(ctx); err != nil {
+			report.Status, report.Database = "unavailable", err.Error()
+		}
+		open := 0
+		for _, p := range report.Providers {
+			if p.Circuit == fetcher.BreakerOpen.String() {
+				open++
+			}
+		}
+		if open > 0 && open == len(report.Providers) {
+			report.Status = "unavailable"
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if report.Status != "ok" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(report)
+	})
+	return mux
+}
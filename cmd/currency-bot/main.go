@@ -2,67 +2,102 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
-	"github.com/akyTheDev/currency-bot/internal/bot"
 	"github.com/akyTheDev/currency-bot/internal/config"
-	"github.com/akyTheDev/currency-bot/internal/fetcher"
-	"github.com/akyTheDev/currency-bot/internal/repository"
-	"github.com/akyTheDev/currency-bot/internal/service"
-	"github.com/akyTheDev/currency-bot/internal/storage"
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
+type command struct {
+	name  string
+	usage string
+	// bot and db are whether the command needs the Telegram token and the
+	// database URL.
+	bot, db bool
+	run     func(ctx context.Context, a *app, args []string) error
+}
+
+var commands = []command{
+	{name: "serve", usage: "serve", bot: true, db: true, run: runServe},
+	{name: "migrate", usage: "migrate [up|down|status|redo|up-to VERSION|down-to VERSION]", db: true, run: runMigrate},
+	{name: "fetch", usage: "fetch [-json]", run: runFetch},
+	{name: "send-test", usage: "send-test CHAT_ID", bot: true, db: true, run: runSendTest},
+	{name: "users", usage: "users list|count|remove CHAT_ID", db: true, run: runUsers},
+	{name: "broadcast", usage: "broadcast --file PATH", bot: true, db: true, run: runBroadcast},
+	{name: "audit", usage: "audit [-chat CHAT_ID] [-type TYPE] [-since DURATION] [-limit N]", db: true, run: runAudit},
+}
+
 func main() {
 	logger := log.New(os.Stdout, "", log.Ldate|log.Ltime)
 
-	cfg, err := config.Load()
-	if err != nil {
-		logger.Fatalf("Config couldn't be loaded: %v", err)
+	fs := flag.NewFlagSet("currency-bot", flag.ContinueOnError)
+	fs.Usage = func() { usage(fs) }
+	flags := config.BindFlags(fs)
+	if err := fs.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		os.Exit(2)
 	}
 
-	db, err := storage.OpenDB(cfg.DatabaseURL)
-	if err != nil {
-		logger.Fatalf("Couldn't connected to db: %v", err)
+	name, args := "serve", []string{}
+	if fs.NArg() > 0 {
+		name, args = fs.Arg(0), fs.Args()[1:]
 	}
 
-	// Fetcher
-	fetcher := fetcher.NewTCMBClient(fetcher.TcmbUrl, 60)
+	if name == "help" {
+		usage(fs)
+		return
+	}
 
-	// Repositories
-	userRepository := repository.NewPostgresUserRepository(db)
+	cmd, ok := findCommand(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage(fs)
+		os.Exit(2)
+	}
 
-	// Services
-	userService := service.NewUserService(userRepository, logger)
-	notifyService := service.NewNotifyService(logger, userRepository, fetcher)
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
 
-	botAPI, err := tgbotapi.NewBotAPI(cfg.TelegramToken)
+	a, err := newApp(logger, flags)
+	if err == nil {
+		err = a.cfg.Validate(cmd.bot, cmd.db)
+	}
 	if err != nil {
-		logger.Fatalf("Couldn't create a bot")
+		logger.Fatalf("Config couldn't be loaded: %v", err)
 	}
 
-	commands := []tgbotapi.BotCommand{
-		{Command: bot.CmdRegister, Description: bot.HelpRegister},
-		{Command: bot.CmdDelete, Description: bot.HelpDelete},
+	err = cmd.run(ctx, a, args)
+	a.close()
+	if err != nil {
+		logger.Fatalf("%s: %v", cmd.name, err)
 	}
+}
 
-	setCmdConfig := tgbotapi.NewSetMyCommands(commands...)
-	if _, err := botAPI.Request(setCmdConfig); err != nil {
-		logger.Fatalf("could not set bot commands: %v", err)
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
 	}
+	return command{}, false
+}
 
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
-	defer cancel()
-
-	handler := bot.NewBotHandler(ctx, botAPI, logger, userService, notifyService)
-	go handler.Start()
-
-	logger.Println("Bot is running...")
-	<-ctx.Done()
-	logger.Println("Shutting down...")
-	time.Sleep(1 * time.Second)
+func usage(fs *flag.FlagSet) {
+	w := fs.Output()
+	fmt.Fprintln(w, "Usage: currency-bot [flags] <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %s\n", cmd.usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fs.PrintDefaults()
 }
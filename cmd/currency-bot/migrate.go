@@ -0,0 +1,21 @@
+package main
+
+import (
+	"context"
+
+	"github.com/akyTheDev/currency-bot/internal/storage"
+	"github.com/akyTheDev/currency-bot/migrations"
+)
+
+func runMigrate(ctx context.Context, a *app, args []string) error {
+	command := "up"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
+
+	if err := a.openDB(); err != nil {
+		return err
+	}
+
+	return storage.Migrate(ctx, a.db, migrations.FS, a.logger, command, args...)
+}
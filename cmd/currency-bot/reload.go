@@ -0,0 +1,54 @@
+package main
+
+import (
+	"reflect"
+
+	"github.com/akyTheDev/currency-bot/internal/bot"
+	"github.com/akyTheDev/currency-bot/internal/config"
+)
+
+// reload re-reads the configuration and swaps the parts that can change
+// without a restart. Invalid configuration leaves the running one untouched.
+func (a *app) reload(handler *bot.BotHandler) {
+	a.logger.Println("Reloading configuration...")
+
+	cfg, err := config.Load(a.flags)
+	if err == nil {
+		err = cfg.Validate(true, true)
+	}
+	if err != nil {
+		a.logger.Printf("Reload rejected: %v\n", err)
+		return
+	}
+
+	settings, err := newSettings(cfg)
+	if err != nil {
+		a.logger.Printf("Reload rejected: %v\n", err)
+		return
+	}
+
+	if cfg.Telegram != a.cfg.Telegram || cfg.Database != a.cfg.Database || cfg.HTTP != a.cfg.HTTP || cfg.Updates != a.cfg.Updates || cfg.RateLimit != a.cfg.RateLimit {
+		a.logger.Println("Reload: telegram, database, http, updates and rate_limit settings require a restart; keeping current values")
+	}
+	localeChanged := !reflect.DeepEqual(cfg.Locale, a.cfg.Locale)
+
+	// Keeping the providers keeps their cached bulletins.
+	if !reflect.DeepEqual(cfg.Fetch, a.cfg.Fetch) {
+		a.fetcher.Store(newFetcher(cfg.Fetch))
+	}
+	handler.ApplySettings(settings)
+
+	if localeChanged {
+		if err := handler.RegisterCommands(); err != nil {
+			a.logger.Printf("Reload: could not update bot commands: %v\n", err)
+		}
+	}
+
+	a.cfg.Schedule = cfg.Schedule
+	a.cfg.Fetch = cfg.Fetch
+	a.cfg.Admins = cfg.Admins
+	a.cfg.Templates = cfg.Templates
+	a.cfg.Locale = cfg.Locale
+
+	a.logger.Println("Configuration reloaded")
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+func runSendTest(ctx context.Context, a *app, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: send-test CHAT_ID")
+	}
+
+	chatID, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid CHAT_ID %q: %w", args[0], err)
+	}
+
+	handler, err := a.newBotHandler(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := handler.SendRate(chatID); err != nil {
+		return fmt.Errorf("send to chat_id=%d: %w", chatID, err)
+	}
+
+	fmt.Printf("Test message sent to chat_id=%d\n", chatID)
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout bounds how long queued updates may take to drain.
+const shutdownTimeout = 30 * time.Second
+
+func runServe(ctx context.Context, a *app, args []string) error {
+	handler, err := a.newBotHandler(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := handler.RegisterCommands(); err != nil {
+		return fmt.Errorf("could not set bot commands: %w", err)
+	}
+
+	if addr := a.cfg.HTTP.MetricsAddr; addr != "" {
+		serveHTTP(ctx, a.logger, "metrics", addr, metricsHandler())
+	}
+	if addr := a.cfg.HTTP.HealthAddr; addr != "" {
+		serveHTTP(ctx, a.logger, "health", addr, healthHandler(a.db, a.fetcher))
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	stopped := make(chan struct{})
+	go func() {
+		handler.Start(a.cfg.Updates.Workers, a.cfg.Updates.QueueSize)
+		close(stopped)
+	}()
+
+	a.logger.Println("Bot is running...")
+	for {
+		select {
+		case <-hup:
+			a.reload(handler)
+		case <-ctx.Done():
+			a.logger.Println("Shutting down...")
+			select {
+			case <-stopped:
+			case <-time.After(shutdownTimeout):
+				a.logger.Printf("Shutdown: updates still running after %s; exiting anyway\n", shutdownTimeout)
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/akyTheDev/currency-bot/internal/models"
+)
+
+const usersUsage = "usage: users list|count|remove CHAT_ID"
+
+func runUsers(ctx context.Context, a *app, args []string) error {
+	if len(args) == 0 {
+		return errors.New(usersUsage)
+	}
+
+	if err := a.openDB(); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		users, err := a.userService.List()
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tCHAT_ID")
+		for _, user := range users {
+			fmt.Fprintf(w, "%d\t%d\n", user.ID, user.ChatID)
+		}
+		return w.Flush()
+	case "count":
+		count, err := a.userService.Count()
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(count)
+		return nil
+	case "remove":
+		if len(args) != 2 {
+			return errors.New(usersUsage)
+		}
+
+		chatID, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid CHAT_ID %q: %w", args[1], err)
+		}
+
+		if err := a.userService.Remove(chatID, models.ActorCLI); err != nil {
+			return err
+		}
+
+		fmt.Printf("User with chat_id=%d removed\n", chatID)
+		return nil
+	default:
+		return errors.New(usersUsage)
+	}
+}
@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	cbSubscribe = "s"
+	cbDelete    = "d"
+	cbRefresh   = "r"
+
+	// Telegram rejects buttons whose callback data exceeds 64 bytes.
+	maxCallbackData = 64
+)
+
+var errInvalidCallback = errors.New("invalid callback data")
+
+// callbackSigner encodes button payloads as "action:arg,arg.sig", where sig
+// is a truncated HMAC over the chat ID and payload. Data copied to another
+// chat or edited by the client fails verification.
+type callbackSigner struct {
+	key []byte
+}
+
+func newCallbackSigner(secret string) *callbackSigner {
+	key := sha256.Sum256([]byte("callback:" + secret))
+	return &callbackSigner{key: key[:]}
+}
+
+func (s *callbackSigner) sign(chatID int64, payload string) string {
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "%d|%s", chatID, payload)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:6])
+}
+
+func (s *callbackSigner) encode(chatID int64, action string, args ...string) string {
+	payload := action
+	if len(args) > 0 {
+		payload += ":" + strings.Join(args, ",")
+	}
+	return payload + "." + s.sign(chatID, payload)
+}
+
+func (s *callbackSigner) decode(chatID int64, data string) (string, []string, error) {
+	i := strings.LastIndexByte(data, '.')
+	if i < 0 {
+		return "", nil, errInvalidCallback
+	}
+
+	payload, sig := data[:i], data[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(s.sign(chatID, payload))) {
+		return "", nil, errInvalidCallback
+	}
+
+	action, rawArgs, _ := strings.Cut(payload, ":")
+	var args []string
+	if rawArgs != "" {
+		args = strings.Split(rawArgs, ",")
+	}
+	return action, args, nil
+}
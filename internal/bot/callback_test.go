@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCallbackSigner(t *testing.T) {
+	signer := newCallbackSigner("token")
+
+	data := signer.encode(42, cbRefresh, "USD", "EUR", "GBP", "CHF", "JPY")
+	if len(data) > maxCallbackData {
+		t.Fatalf("len(%q) = %d; want at most %d", data, len(data), maxCallbackData)
+	}
+
+	action, args, err := signer.decode(42, data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if action != cbRefresh || strings.Join(args, ",") != "USD,EUR,GBP,CHF,JPY" {
+		t.Errorf("decode = %q %v; want %q [USD EUR GBP CHF JPY]", action, args, cbRefresh)
+	}
+
+	action, args, err = signer.decode(42, signer.encode(42, cbDelete))
+	if err != nil || action != cbDelete || args != nil {
+		t.Errorf("decode without args = %q %v %v", action, args, err)
+	}
+}
+
+func TestCallbackSigner_Rejects(t *testing.T) {
+	signer := newCallbackSigner("token")
+	data := signer.encode(42, cbSubscribe, "USD")
+
+	tests := []struct {
+		name   string
+		chatID int64
+		data   string
+	}{
+		{name: "OtherChat", chatID: 43, data: data},
+		{name: "TamperedArgs", chatID: 42, data: strings.Replace(data, "USD", "EUR", 1)},
+		{name: "OtherSecret", chatID: 42, data: newCallbackSigner("other").encode(42, cbSubscribe, "USD")},
+		{name: "NoSignature", chatID: 42, data: "s:USD"},
+		{name: "Empty", chatID: 42, data: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, _, err := signer.decode(tc.chatID, tc.data); !errors.Is(err, errInvalidCallback) {
+				t.Errorf("decode(%q) error = %v; want %v", tc.data, err, errInvalidCallback)
+			}
+		})
+	}
+}
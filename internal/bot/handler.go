@@ -2,26 +2,61 @@ package bot
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
 
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	"github.com/akyTheDev/currency-bot/internal/ratelimit"
 	"github.com/akyTheDev/currency-bot/internal/service"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 const (
-	CmdRegister    = "register"
-	CmdDelete      = "delete"
-	HelpRegister   = "Register to receive hourly EUR→TRY updates"
-	HelpDelete     = "Unregister from receiving updates"
-	UnknownCommand = "Unknown command. Use /register or /delete."
+	CmdRegister  = "register"
+	CmdDelete    = "delete"
+	CmdStats     = "stats"
+	CmdBroadcast = "broadcast"
+	CmdFetchNow  = "fetchnow"
+	CmdUser      = "user"
+	CmdLanguage  = "language"
+	CmdSubscribe = "subscribe"
+	CmdRate      = "rate"
+	CmdChannel   = "channel"
+	CmdLive      = "live"
+	CmdDigest    = "digest"
+	CmdPrices    = "prices"
 )
 
 type BotHandler struct {
-	bot           *tgbotapi.BotAPI
-	logger        *log.Logger
-	userService   *service.UserService
-	notifyService *service.NotifyService
-	context       context.Context
+	bot             *tgbotapi.BotAPI
+	logger          *log.Logger
+	userService     *service.UserService
+	notifyService   *service.NotifyService
+	adminService    *service.AdminService
+	prefService     *service.PreferenceService
+	subService      *service.SubscriptionService
+	updateService   *service.UpdateService
+	digestService   *service.DigestService
+	offsets         *offsetTracker
+	callbacks       *callbackSigner
+	inline          *inlineCache
+	trends          *rateTrends
+	router          *Router
+	limiter         ratelimit.Limiter
+	settings        atomic.Pointer[Settings]
+	broadcasts      *pendingBroadcasts
+	settingsChanged chan struct{}
+	fetchNow        chan struct{}
+	// background tracks work started outside the worker pool, such as
+	// broadcasts, so Start can wait for it.
+	background sync.WaitGroup
+	context    context.Context
 }
 
 func NewBotHandler(
@@ -30,57 +65,202 @@ func NewBotHandler(
 	logger *log.Logger,
 	userService *service.UserService,
 	notifyService *service.NotifyService,
+	adminService *service.AdminService,
+	prefService *service.PreferenceService,
+	subService *service.SubscriptionService,
+	updateService *service.UpdateService,
+	digestService *service.DigestService,
+	limiter ratelimit.Limiter,
+	settings *Settings,
 ) *BotHandler {
-	return &BotHandler{
-		context:       context,
-		bot:           bot,
-		logger:        logger,
-		userService:   userService,
-		notifyService: notifyService,
+	h := &BotHandler{
+		context:         context,
+		bot:             bot,
+		logger:          logger,
+		userService:     userService,
+		notifyService:   notifyService,
+		adminService:    adminService,
+		prefService:     prefService,
+		subService:      subService,
+		updateService:   updateService,
+		digestService:   digestService,
+		callbacks:       newCallbackSigner(bot.Token),
+		inline:          newInlineCache(),
+		trends:          newRateTrends(),
+		limiter:         limiter,
+		broadcasts:      newPendingBroadcasts(),
+		settingsChanged: make(chan struct{}, 1),
+		fetchNow:        make(chan struct{}, 1),
 	}
+	h.settings.Store(settings)
+	h.router = h.newRouter()
+	return h
 }
 
-func (h *BotHandler) Start() {
+func (h *BotHandler) ApplySettings(settings *Settings) {
+	h.settings.Store(settings)
+	select {
+	case h.settingsChanged <- struct{}{}:
+	default:
+	}
+}
+
+// Start polls for updates from the offset stored by the previous run and
+// hands them to a pool of workers until the handler's context ends, then
+// waits for queued updates and background work to finish.
+func (h *BotHandler) Start(workers, queueSize int) {
+	offset := h.updateService.Offset()
+	h.offsets = newOffsetTracker(offset, h.updateService.ProcessedAfter(offset))
+
 	updateConfig := tgbotapi.NewUpdate(0)
+	if offset > 0 {
+		updateConfig.Offset = offset + 1
+		h.logger.Printf("BotHandler: resuming after update_id=%d\n", offset)
+	}
 	updateConfig.Timeout = 30
 
+	pool := newWorkerPool(workers, queueSize, h.logger, h.replyPanic)
+
 	updates := h.bot.GetUpdatesChan(updateConfig)
 	go h.startNotify(h.context)
+	go h.pruneUpdates(h.context)
+	go h.saveOffsets(h.context)
 	for {
 		select {
 		case update := <-updates:
-			if update.Message == nil || !update.Message.IsCommand() {
-				continue
-			}
-			go h.handleUpdate(update)
+			h.dispatch(pool, update)
 		case <-h.context.Done():
-			h.logger.Println("BotHandler: stopping due to context cancellation")
+			h.logger.Println("BotHandler: stopping due to context cancellation; draining queued updates")
+			h.bot.StopReceivingUpdates()
+			pool.close()
+			h.background.Wait()
+			h.saveOffset()
+			h.logger.Println("BotHandler: stopped")
 			return
 		}
 	}
+}
 
+// goBackground runs fn on its own goroutine, recovering panics, and tracks it
+// so shutdown waits for it.
+func (h *BotHandler) goBackground(name string, fn func()) {
+	h.background.Add(1)
+	go func() {
+		defer h.background.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				handlerPanics.Add(1)
+				h.logger.Printf("PANIC in %s: %v\n%s", name, r, debug.Stack())
+			}
+		}()
+		fn()
+	}()
 }
 
-func (h *BotHandler) handleUpdate(update tgbotapi.Update) {
-	msg := update.Message
-	chatID := msg.Chat.ID
-	cmd := msg.Command()
+func (h *BotHandler) dispatch(pool *workerPool, update tgbotapi.Update) {
+	h.offsets.begin(update.UpdateID)
 
-	h.logger.Printf("Received command: %s from chat_id=%d\n", cmd, chatID)
+	chatID, handle := h.route(update)
+	if handle == nil {
+		h.offsets.done(update.UpdateID)
+		return
+	}
+	pool.submit(h.context, chatID, h.once(update.UpdateID, handle))
+}
 
-	switch cmd {
-	case CmdRegister:
-		h.handleRegister(chatID)
-	case CmdDelete:
-		h.handleDelete(chatID)
-	default:
-		h.replyText(chatID, UnknownCommand)
+// route picks the chat an update is ordered by and its handler, or nil for
+// updates the bot ignores. The handler reports whether it acted on the
+// update.
+func (h *BotHandler) route(update tgbotapi.Update) (int64, func() bool) {
+	switch {
+	case update.CallbackQuery != nil:
+		query := update.CallbackQuery
+		chatID := query.From.ID
+		if query.Message != nil {
+			chatID = query.Message.Chat.ID
+		}
+		return chatID, func() bool { h.handleCallback(query); return true }
+	case update.InlineQuery != nil:
+		query := update.InlineQuery
+		return query.From.ID, func() bool { h.handleInlineQuery(query); return true }
+	case update.Message != nil && update.Message.IsCommand():
+		msg := update.Message
+		return msg.Chat.ID, func() bool { return h.router.Dispatch(msg) }
+	case update.ChannelPost != nil && update.ChannelPost.IsCommand():
+		post := update.ChannelPost
+		return post.Chat.ID, func() bool { return h.router.Dispatch(post) }
 	}
+	return 0, nil
+}
+
+func (h *BotHandler) replyPanic(chatID int64) {
+	locale := h.settings.Load().chatLocale(h.prefService.Language(chatID))
+	h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
+}
+
+// RegisterCommands publishes the command list for every configured locale,
+// plus the default locale for clients using any other language.
+func (h *BotHandler) RegisterCommands() error {
+	settings := h.settings.Load()
+
+	requests := []tgbotapi.SetMyCommandsConfig{tgbotapi.NewSetMyCommands(h.router.Commands(settings.DefaultLocale)...)}
+	for _, locale := range settings.Locales {
+		requests = append(requests, tgbotapi.NewSetMyCommandsWithScopeAndLanguage(
+			tgbotapi.NewBotCommandScopeDefault(), locale, h.router.Commands(locale)...,
+		))
+	}
+
+	for _, req := range requests {
+		if _, err := h.bot.Request(req); err != nil {
+			return fmt.Errorf("set commands %q: %w", req.LanguageCode, err)
+		}
+	}
+	return nil
 }
 
 func (h *BotHandler) replyText(chatID int64, text string) {
-	msg := tgbotapi.NewMessage(chatID, text)
+	h.reply(tgbotapi.NewMessage(chatID, text))
+}
+
+func (h *BotHandler) reply(msg tgbotapi.MessageConfig) {
 	if _, err := h.bot.Send(msg); err != nil {
-		h.logger.Printf("replyText: failed to send to chat_id=%d: %v\n", chatID, err)
+		h.logger.Printf("reply: failed to send to chat_id=%d: %v\n", msg.ChatID, err)
+		return
+	}
+	h.adminService.RecordSent(1)
+}
+
+// edit changes a message in place. Edits that leave the message unchanged
+// are not errors.
+func (h *BotHandler) edit(c tgbotapi.Chattable) {
+	if _, err := h.bot.Send(c); err != nil && !isNotModified(err) {
+		h.logger.Printf("edit: %v\n", err)
+	}
+}
+
+func isNotModified(err error) bool {
+	var tgErr *tgbotapi.Error
+	return errors.As(err, &tgErr) && strings.Contains(tgErr.Message, "message is not modified")
+}
+
+// deliver sends a message to a subscriber, deactivating chats that have
+// blocked the bot.
+func (h *BotHandler) deliver(msg tgbotapi.MessageConfig) bool {
+	if _, err := h.bot.Send(msg); err != nil {
+		h.sendFailed(msg.ChatID, err)
+		return false
+	}
+	return true
+}
+
+func (h *BotHandler) sendFailed(chatID int64, err error) {
+	h.logger.Printf("deliver: failed to send to chat_id=%d: %v\n", chatID, err)
+
+	var tgErr *tgbotapi.Error
+	if errors.As(err, &tgErr) && tgErr.Code == http.StatusForbidden {
+		h.logger.Printf("deliver: chat_id=%d blocked the bot; deactivating\n", chatID)
+		if err := h.userService.Deactivate(chatID, "bot blocked by user"); err != nil {
+			h.logger.Printf("deliver: failed to deactivate chat_id=%d: %v\n", chatID, err)
+		}
 	}
 }
@@ -0,0 +1,153 @@
+package bot
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const broadcastTTL = 5 * time.Minute
+
+type pendingBroadcast struct {
+	text      string
+	expiresAt time.Time
+}
+
+type pendingBroadcasts struct {
+	mu      sync.Mutex
+	pending map[int64]pendingBroadcast
+}
+
+func newPendingBroadcasts() *pendingBroadcasts {
+	return &pendingBroadcasts{pending: make(map[int64]pendingBroadcast)}
+}
+
+func (p *pendingBroadcasts) put(adminID int64, text string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.pending[adminID] = pendingBroadcast{text: text, expiresAt: time.Now().Add(broadcastTTL)}
+}
+
+func (p *pendingBroadcasts) take(adminID int64) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	b, ok := p.pending[adminID]
+	delete(p.pending, adminID)
+	if !ok || time.Now().After(b.expiresAt) {
+		return "", false
+	}
+	return b.text, true
+}
+
+func senderID(msg *tgbotapi.Message) int64 {
+	if msg.From != nil {
+		return msg.From.ID
+	}
+	return msg.Chat.ID
+}
+
+func (h *BotHandler) isAdmin(userID int64) bool {
+	return h.settings.Load().IsAdmin(userID) || h.adminService.IsAdmin(userID)
+}
+
+func (h *BotHandler) handleStats(req *Request) {
+	locale := req.Locale
+	stats, err := h.adminService.Stats()
+	if err != nil {
+		h.replyText(req.ChatID, i18n.T(locale, i18n.ErrorGeneric))
+		return
+	}
+
+	h.replyText(req.ChatID, i18n.T(locale, i18n.AdminStats,
+		i18n.FormatInt(locale, stats.Subscribers),
+		i18n.FormatInt(locale, stats.Active),
+		i18n.FormatInt(locale, stats.Blocked),
+		i18n.FormatInt(locale, stats.SentToday),
+	))
+}
+
+func (h *BotHandler) handleBroadcast(req *Request) {
+	chatID, locale := req.ChatID, req.Locale
+	adminID := senderID(req.Message)
+	args := req.Args[0]
+
+	switch args {
+	case "cancel":
+		if _, ok := h.broadcasts.take(adminID); !ok {
+			h.replyText(chatID, i18n.T(locale, i18n.AdminBroadcastNone))
+			return
+		}
+		h.replyText(chatID, i18n.T(locale, i18n.AdminBroadcastDiscard))
+	case "confirm":
+		text, ok := h.broadcasts.take(adminID)
+		if !ok {
+			h.replyText(chatID, i18n.T(locale, i18n.AdminBroadcastNone))
+			return
+		}
+		h.goBackground("broadcast", func() {
+			sent, err := h.Broadcast(text)
+			if err != nil {
+				h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
+				return
+			}
+			h.logger.Printf("AUDIT: broadcast sent by user_id=%d to %d users\n", adminID, sent)
+			h.replyText(chatID, i18n.N(locale, i18n.AdminBroadcastSent, sent))
+		})
+	default:
+		count, err := h.userService.CountActive()
+		if err != nil {
+			h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
+			return
+		}
+		h.broadcasts.put(adminID, args)
+		h.replyText(chatID, i18n.N(locale, i18n.AdminBroadcastPreview, count, args))
+	}
+}
+
+// handleFetchNow asks the notify loop for an extra run; a request made while
+// one is already pending is folded into it.
+func (h *BotHandler) handleFetchNow(req *Request) {
+	select {
+	case h.fetchNow <- struct{}{}:
+	default:
+	}
+	h.replyText(req.ChatID, i18n.T(req.Locale, i18n.AdminFetchNow))
+}
+
+func (h *BotHandler) handleUser(req *Request) {
+	chatID, locale := req.ChatID, req.Locale
+	targetID, _ := strconv.ParseInt(req.Args[0], 10, 64)
+
+	user, err := h.userService.Get(targetID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			h.replyText(chatID, i18n.T(locale, i18n.AdminUserNotFound, targetID))
+			return
+		}
+		h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
+		return
+	}
+
+	status := i18n.T(locale, i18n.AdminUserActive)
+	if user.BlockedAt != nil {
+		status = i18n.T(locale, i18n.AdminUserBlockedSince, user.BlockedAt.Format("2006-01-02 15:04"))
+	}
+
+	h.replyText(chatID, i18n.T(locale, i18n.AdminUserInfo,
+		user.ChatID, user.ID, user.CreatedAt.Format("2006-01-02 15:04"), status,
+	))
+}
+
+func chatIDArg(raw string) ([]string, error) {
+	arg := strings.TrimSpace(raw)
+	if _, err := strconv.ParseInt(arg, 10, 64); err != nil {
+		return nil, errBadArgs
+	}
+	return []string{arg}, nil
+}
@@ -0,0 +1,114 @@
+package bot
+
+import (
+	"slices"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func (h *BotHandler) handleCallback(query *tgbotapi.CallbackQuery) {
+	if query.Message == nil {
+		h.answerCallback(query.ID, "")
+		return
+	}
+
+	chatID := query.Message.Chat.ID
+	locale := h.locale(chatID, query.From)
+
+	action, args, err := h.callbacks.decode(chatID, query.Data)
+	if err != nil {
+		h.logger.Printf("handleCallback: rejected data=%q from chat_id=%d: %v\n", query.Data, chatID, err)
+		h.answerCallback(query.ID, i18n.T(locale, i18n.CallbackInvalid))
+		return
+	}
+
+	h.logger.Printf("Received callback: %s %v from chat_id=%d\n", action, args, chatID)
+
+	if (action == cbSubscribe || action == cbDelete) && !h.isChatAdmin(query.Message.Chat, query.From.ID) {
+		h.answerCallback(query.ID, i18n.T(locale, i18n.GroupAdminOnly))
+		return
+	}
+
+	switch {
+	case action == cbSubscribe && len(args) == 1:
+		code, err := currency.Parse(args[0])
+		if err != nil {
+			h.answerCallback(query.ID, i18n.T(locale, i18n.CallbackInvalid))
+			return
+		}
+		h.handleSubscribeCallback(query, locale, code)
+	case action == cbDelete && len(args) == 1:
+		h.handleDeleteCallback(query, locale, args[0] == "y")
+	case action == cbRefresh:
+		codes, err := currency.ParseAll(args)
+		if err != nil {
+			h.answerCallback(query.ID, i18n.T(locale, i18n.CallbackInvalid))
+			return
+		}
+		h.handleRefreshCallback(query, locale, codes)
+	default:
+		h.answerCallback(query.ID, i18n.T(locale, i18n.CallbackInvalid))
+	}
+}
+
+func (h *BotHandler) handleSubscribeCallback(query *tgbotapi.CallbackQuery, locale string, code currency.Code) {
+	chatID := query.Message.Chat.ID
+
+	if !slices.Contains(h.settings.Load().Currencies, code) {
+		h.answerCallback(query.ID, i18n.T(locale, i18n.RateUnknownCurrency, code))
+		return
+	}
+
+	subscribed, err := h.subService.Toggle(chatID, code, query.From.ID)
+	if err != nil {
+		h.answerCallback(query.ID, i18n.T(locale, i18n.ErrorGeneric))
+		return
+	}
+
+	key := i18n.SubscribeRemoved
+	if subscribed {
+		key = i18n.SubscribeAdded
+	}
+	h.answerCallback(query.ID, i18n.T(locale, key, code))
+
+	current, err := h.subService.Currencies(chatID)
+	if err != nil {
+		return
+	}
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, query.Message.MessageID, h.subscribeKeyboard(chatID, current))
+	h.edit(edit)
+}
+
+func (h *BotHandler) handleDeleteCallback(query *tgbotapi.CallbackQuery, locale string, confirmed bool) {
+	chatID := query.Message.Chat.ID
+	h.answerCallback(query.ID, "")
+
+	text := i18n.T(locale, i18n.DeleteCancelled)
+	if confirmed {
+		text = h.deleteUser(chatID, query.From.ID, locale)
+	}
+	h.edit(tgbotapi.NewEditMessageText(chatID, query.Message.MessageID, text))
+}
+
+func (h *BotHandler) handleRefreshCallback(query *tgbotapi.CallbackQuery, locale string, codes []currency.Code) {
+	chatID := query.Message.Chat.ID
+
+	rates, err := h.chatRates(chatID, codes)
+	if err != nil {
+		h.answerCallback(query.ID, i18n.T(locale, i18n.ErrorGeneric))
+		return
+	}
+
+	h.answerCallback(query.ID, i18n.T(locale, i18n.RateRefreshed))
+	h.edit(tgbotapi.NewEditMessageTextAndMarkup(
+		chatID, query.Message.MessageID, h.ratesMessage(rates, locale), h.refreshKeyboard(chatID, locale, codes),
+	))
+}
+
+func (h *BotHandler) answerCallback(queryID, text string) {
+	if _, err := h.bot.Request(tgbotapi.NewCallback(queryID, text)); err != nil {
+		h.logger.Printf("answerCallback: %v\n", err)
+	}
+}
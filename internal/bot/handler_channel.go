@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const channelDelete = "delete"
+
+// handleChannel registers a channel, or removes it with "delete", on behalf
+// of one of its administrators. The bot has to be an administrator of the
+// channel that may post messages.
+func (h *BotHandler) handleChannel(req *Request) {
+	chatID, locale := req.ChatID, req.Locale
+	name := req.Args[0]
+
+	channel, err := h.bot.GetChat(tgbotapi.ChatInfoConfig{ChatConfig: channelConfig(name)})
+	if err != nil || !channel.IsChannel() {
+		h.replyText(chatID, i18n.T(locale, i18n.ChannelNotFound, name))
+		return
+	}
+
+	self, err := h.bot.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: channel.ID, UserID: h.bot.Self.ID},
+	})
+	if err != nil || !self.IsAdministrator() || !self.CanPostMessages {
+		h.replyText(chatID, i18n.T(locale, i18n.ChannelBotNotAdmin, name))
+		return
+	}
+
+	if req.Message.From == nil || !h.isChatAdmin(&channel, req.Message.From.ID) {
+		h.logger.Printf("AUDIT: channel change for chat_id=%d refused for chat_id=%d\n", channel.ID, chatID)
+		h.replyText(chatID, i18n.T(locale, i18n.ChannelNotAdmin, name))
+		return
+	}
+
+	if len(req.Args) > 1 {
+		h.replyText(chatID, h.removeChannel(channel.ID, req.Message.From.ID, name, locale))
+		return
+	}
+	h.replyText(chatID, h.registerChannel(channel.ID, req.Message.From.ID, name, locale))
+}
+
+func (h *BotHandler) registerChannel(channelID, userID int64, name, locale string) string {
+	if err := h.userService.Register(channelID, userID); err != nil {
+		if errors.Is(err, domain.ErrUserAlreadyExists) {
+			return i18n.T(locale, i18n.ChannelAlready, name)
+		}
+		h.logger.Printf("registerChannel error for chat_id=%d, error: %v\n", channelID, err)
+		return i18n.T(locale, i18n.ErrorGeneric)
+	}
+
+	h.prefService.InitLanguage(channelID, locale)
+	return i18n.T(locale, i18n.ChannelRegistered, name)
+}
+
+func (h *BotHandler) removeChannel(channelID, userID int64, name, locale string) string {
+	if err := h.userService.Delete(channelID, userID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return i18n.T(locale, i18n.ChannelMissing, name)
+		}
+		h.logger.Printf("removeChannel error for chat_id=%d, error: %v\n", channelID, err)
+		return i18n.T(locale, i18n.ErrorGeneric)
+	}
+	return i18n.T(locale, i18n.ChannelRemoved, name)
+}
+
+// channelConfig addresses a channel by @username or numeric chat id.
+func channelConfig(name string) tgbotapi.ChatConfig {
+	if id, err := strconv.ParseInt(name, 10, 64); err == nil {
+		return tgbotapi.ChatConfig{ChatID: id}
+	}
+	return tgbotapi.ChatConfig{SuperGroupUsername: name}
+}
+
+// channelArgs accepts "@channel" or a chat id, optionally followed by
+// "delete".
+func channelArgs(raw string) ([]string, error) {
+	args := strings.Fields(raw)
+	if len(args) == 0 || len(args) > 2 {
+		return nil, errBadArgs
+	}
+	if len(args) == 2 && !strings.EqualFold(args[1], channelDelete) {
+		return nil, errBadArgs
+	}
+
+	name := args[0]
+	if _, err := strconv.ParseInt(name, 10, 64); err != nil && (!strings.HasPrefix(name, "@") || len(name) < 2) {
+		return nil, errBadArgs
+	}
+	return args, nil
+}
@@ -0,0 +1,44 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChannelArgs(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{raw: "@rates", want: "@rates"},
+		{raw: "-1001234 delete", want: "-1001234|delete"},
+		{raw: "@rates DELETE", want: "@rates|DELETE"},
+		{raw: ""},
+		{raw: "@"},
+		{raw: "rates"},
+		{raw: "@rates remove"},
+		{raw: "@rates delete now"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.raw, func(t *testing.T) {
+			args, err := channelArgs(tc.raw)
+			if tc.want == "" {
+				if err == nil {
+					t.Errorf("channelArgs(%q) = %v; want an error", tc.raw, args)
+				}
+				return
+			}
+			if err != nil || strings.Join(args, "|") != tc.want {
+				t.Errorf("channelArgs(%q) = %v, %v; want %s", tc.raw, args, err, tc.want)
+			}
+		})
+	}
+
+	if cfg := channelConfig("-1001234"); cfg.ChatID != -1001234 || cfg.SuperGroupUsername != "" {
+		t.Errorf("channelConfig(id) = %+v", cfg)
+	}
+	if cfg := channelConfig("@rates"); cfg.SuperGroupUsername != "@rates" {
+		t.Errorf("channelConfig(@name) = %+v", cfg)
+	}
+}
@@ -4,20 +4,27 @@ import (
 	"errors"
 
 	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-func (h *BotHandler) handleDelete(chatID int64) {
-	err := h.userService.Delete(chatID)
+func (h *BotHandler) handleDelete(req *Request) {
+	chatID, locale := req.ChatID, req.Locale
+	reply := tgbotapi.NewMessage(chatID, i18n.T(locale, i18n.DeleteConfirm))
+	reply.ReplyMarkup = h.deleteKeyboard(chatID, locale)
+	h.reply(reply)
+}
+
+func (h *BotHandler) deleteUser(chatID, userID int64, locale string) string {
+	err := h.userService.Delete(chatID, userID)
 
 	if err != nil {
 		if errors.Is(err, domain.ErrUserNotFound) {
-			h.replyText(chatID, "You are not registered!")
-			return
+			return i18n.T(locale, i18n.DeleteMissing)
 		}
 		h.logger.Printf("handleDelete error for chat_id=%d, error: %v\n", chatID, err)
-		h.replyText(chatID, "An unexpected error occured. Please try again later.")
-		return
+		return i18n.T(locale, i18n.ErrorGeneric)
 	}
 
-	h.replyText(chatID, "🗑️ You have been unregistered. You will no longer receive updates.")
+	return i18n.T(locale, i18n.DeleteSuccess)
 }
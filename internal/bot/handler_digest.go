@@ -0,0 +1,153 @@
+package bot
+
+import (
+	"strings"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	"github.com/akyTheDev/currency-bot/internal/service"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const digestOff = "off"
+
+func (h *BotHandler) handleDigest(req *Request) {
+	chatID, locale := req.ChatID, req.Locale
+
+	if len(req.Args) == 0 {
+		h.replyText(chatID, i18n.T(locale, digestStatus(h.prefService.Digest(chatID))))
+		return
+	}
+
+	kind := req.Args[0]
+	if kind == digestOff {
+		kind = ""
+	}
+	if err := h.prefService.SetDigest(chatID, kind); err != nil {
+		h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
+		return
+	}
+
+	if kind == "" {
+		h.replyText(chatID, i18n.T(locale, i18n.DigestOff))
+		return
+	}
+	h.replyText(chatID, i18n.T(locale, digestStatus(kind)))
+}
+
+func digestStatus(kind string) string {
+	switch kind {
+	case service.DigestDaily:
+		return i18n.DigestDailyOn
+	case service.DigestWeekly:
+		return i18n.DigestWeeklyOn
+	}
+	return i18n.DigestNone
+}
+
+// sendDigests sends the digests due today to the active chats among ids,
+// once the configured hour has passed in Istanbul.
+func (h *BotHandler) sendDigests(ids []int64, languages map[int64]string, subscriptions map[int64][]currency.Code) {
+	settings := h.settings.Load()
+	now := time.Now().In(service.Istanbul)
+	if now.Hour() < settings.DigestHour {
+		return
+	}
+
+	day := service.Day(now)
+	pending := h.prefService.PendingDigests(day)
+	if len(pending) == 0 {
+		return
+	}
+
+	priceTypes := h.prefService.PriceTypes()
+	texts := make(map[string]string)
+	sent := 0
+	for _, chatID := range ids {
+		kind, ok := pending[chatID]
+		if !ok || !service.DigestDue(kind, day) {
+			continue
+		}
+
+		locale := settings.chatLocale(languages[chatID])
+		codes := subscriptions[chatID]
+		if len(codes) == 0 {
+			codes = []currency.Code{DefaultCurrency}
+		}
+
+		priceType := priceTypes[chatID]
+		key := kind + "|" + locale + "|" + priceType + "|" + strings.Join(currency.Strings(codes), ",")
+		text, ok := texts[key]
+		if !ok {
+			digests, err := h.digestService.Digest(codes, day, priceType)
+			if err != nil {
+				h.logger.Printf("sendDigests: failed to build the digest of chat %d: %v\n", chatID, err)
+				continue
+			}
+			text = digestMessage(kind, digests, locale)
+			texts[key] = text
+		}
+		if text == "" {
+			continue
+		}
+
+		if h.deliver(tgbotapi.NewMessage(chatID, text)) {
+			h.prefService.MarkDigestSent(chatID, day)
+			sent++
+		}
+	}
+
+	if sent > 0 {
+		h.adminService.RecordSent(sent)
+		h.logger.Printf("%d digests have been sent.\n", sent)
+	}
+}
+
+// digestMessage renders digests, or "" when there is no history yet.
+func digestMessage(kind string, digests []service.CurrencyDigest, locale string) string {
+	if len(digests) == 0 {
+		return ""
+	}
+
+	header := i18n.DigestDailyHeader
+	if kind == service.DigestWeekly {
+		header = i18n.DigestWeeklyHeader
+	}
+	lines := []string{i18n.T(locale, header, digests[0].CloseDate.Format("02.01.2006"))}
+
+	for _, d := range digests {
+		lines = append(lines, i18n.T(locale, i18n.DigestLine,
+			d.Code,
+			i18n.FormatNumber(locale, d.Close, 4),
+			percent(locale, d.DayChange, d.HasDayChange),
+			percent(locale, d.WeekChange, d.HasWeekChange),
+			i18n.FormatNumber(locale, d.WeekLow, 4),
+			i18n.FormatNumber(locale, d.WeekHigh, 4),
+			d.Sparkline,
+		))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func percent(locale string, change decimal.Decimal, ok bool) string {
+	if !ok {
+		return i18n.T(locale, i18n.DigestNoChange)
+	}
+	sign := "+"
+	if change.Sign() < 0 {
+		sign, change = "-", change.Neg()
+	}
+	return sign + i18n.T(locale, i18n.DigestPercent, i18n.FormatNumber(locale, change, 2))
+}
+
+func digestArgs(raw string) ([]string, error) {
+	switch arg := strings.ToLower(strings.TrimSpace(raw)); arg {
+	case "":
+		return nil, nil
+	case service.DigestDaily, service.DigestWeekly, digestOff:
+		return []string{arg}, nil
+	}
+	return nil, errBadArgs
+}
@@ -0,0 +1,61 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+	"github.com/akyTheDev/currency-bot/internal/service"
+)
+
+func TestDigestMessage(t *testing.T) {
+	digests := []service.CurrencyDigest{{
+		Code:      "USD",
+		CloseDate: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+		Close:     decimal.MustParse("35.2"),
+		DayChange: decimal.MustParse("0.4567"), HasDayChange: true,
+		WeekChange: decimal.MustParse("-1.5"), HasWeekChange: true,
+		WeekLow: decimal.FromInt(35), WeekHigh: decimal.MustParse("35.8"),
+		Sparkline: "▁▅█",
+	}}
+
+	tests := []struct {
+		kind, locale, want string
+	}{
+		{
+			kind: service.DigestDaily, locale: "en",
+			want: "🗞️ Daily digest, close of 03.01.2025\nUSD 35.2000\n  Day +0.46% · Week -1.50%\n  7 days 35.0000 – 35.8000 ▁▅█",
+		},
+		{
+			kind: service.DigestWeekly, locale: "tr",
+			want: "🗞️ Haftalık özet, 03.01.2025 kapanışı\nUSD 35,2000\n  Gün +%0,46 · Hafta -%1,50\n  7 gün 35,0000 – 35,8000 ▁▅█",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.kind+"/"+tc.locale, func(t *testing.T) {
+			if got := digestMessage(tc.kind, digests, tc.locale); got != tc.want {
+				t.Errorf("digestMessage =\n%s\nwant\n%s", got, tc.want)
+			}
+		})
+	}
+
+	if got := digestMessage(service.DigestDaily, nil, "en"); got != "" {
+		t.Errorf("digestMessage without history = %q; want empty", got)
+	}
+	if got := percent("en", decimal.Decimal{}, false); got != "n/a" {
+		t.Errorf("percent without change = %q", got)
+	}
+}
+
+func TestDigestArgs(t *testing.T) {
+	for raw, want := range map[string]string{"": "", "Daily": "daily", "weekly": "weekly", "off": "off"} {
+		args, err := digestArgs(raw)
+		if err != nil || (want == "" && args != nil) || (want != "" && (len(args) != 1 || args[0] != want)) {
+			t.Errorf("digestArgs(%q) = %v, %v; want %q", raw, args, err, want)
+		}
+	}
+	if _, err := digestArgs("monthly"); err == nil {
+		t.Error("digestArgs(monthly) accepted")
+	}
+}
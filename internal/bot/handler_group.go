@@ -0,0 +1,35 @@
+package bot
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// isChatAdmin reports whether userID may change the updates of chat. Anyone
+// may in a private chat; in groups and channels it takes an administrator.
+func (h *BotHandler) isChatAdmin(chat *tgbotapi.Chat, userID int64) bool {
+	if chat.IsPrivate() {
+		return true
+	}
+
+	member, err := h.bot.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chat.ID, UserID: userID},
+	})
+	if err != nil {
+		h.logger.Printf("getChatMember failed for chat_id=%d user_id=%d: %v\n", chat.ID, userID, err)
+		return false
+	}
+	return member.IsCreator() || member.IsAdministrator()
+}
+
+// isSenderChatAdmin is isChatAdmin for the sender of msg. Channel posts and
+// messages sent anonymously on behalf of a group can only come from its
+// administrators.
+func (h *BotHandler) isSenderChatAdmin(msg *tgbotapi.Message) bool {
+	if msg.Chat.IsChannel() || (msg.SenderChat != nil && msg.SenderChat.ID == msg.Chat.ID) {
+		return true
+	}
+	if msg.From == nil {
+		return false
+	}
+	return h.isChatAdmin(msg.Chat, msg.From.ID)
+}
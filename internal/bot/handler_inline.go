@@ -0,0 +1,79 @@
+package bot
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func (h *BotHandler) handleInlineQuery(query *tgbotapi.InlineQuery) {
+	locale := h.locale(query.From.ID, query.From)
+	results := h.inlineResults(query.Query, locale, h.prefService.PriceType(query.From.ID))
+
+	answer := tgbotapi.InlineConfig{
+		InlineQueryID: query.ID,
+		Results:       results,
+		CacheTime:     inlineCacheTime,
+		IsPersonal:    true,
+	}
+	if _, err := h.bot.Request(answer); err != nil {
+		h.logger.Printf("handleInlineQuery: failed to answer %q: %v\n", query.Query, err)
+	}
+}
+
+func (h *BotHandler) inlineResults(query, locale, priceType string) []interface{} {
+	c, err := parseConversion(query)
+	if err != nil {
+		return []interface{}{}
+	}
+
+	bulletin, fetchedAt, err := h.inline.load(time.Now(), h.notifyService.Bulletin)
+	if err != nil {
+		h.logger.Printf("inlineResults: %v\n", err)
+		return []interface{}{}
+	}
+
+	key := fmt.Sprintf("%s|%s|%s|%s|%s", locale, priceType, c.Amount, c.From, c.To)
+	if results, ok := h.inline.answer(key); ok {
+		return results
+	}
+
+	value, from, to, err := convert(fetcher.WithPrices(bulletin, priceType), c)
+	if err != nil {
+		return []interface{}{}
+	}
+	title := i18n.T(locale, i18n.InlineTitle,
+		i18n.FormatNumber(locale, c.Amount, c.Amount.Places()), c.From,
+		i18n.FormatNumber(locale, value, 2), c.To,
+	)
+
+	quoted := from
+	if from.Code == BaseCurrency {
+		quoted = to
+	}
+	// Prefer the day the bulletin is effective for over when it was fetched.
+	date := fetchedAt.Format(time.DateOnly)
+	if !quoted.Date.IsZero() {
+		date = quoted.Date.Format(time.DateOnly)
+	}
+	prices := i18n.T(locale, i18n.InlinePrices,
+		unitLabel(&quoted),
+		i18n.FormatNumber(locale, quoted.Buying, 4),
+		i18n.FormatNumber(locale, quoted.Selling, 4),
+	)
+
+	article := tgbotapi.NewInlineQueryResultArticle(
+		strings.ToLower(fmt.Sprintf("%s-%s-%s", c.From, c.To, c.Amount)),
+		title,
+		title+"\n"+prices+"\n"+i18n.T(locale, i18n.InlineBulletin, date),
+	)
+	article.Description = prices + " · " + date
+
+	results := []interface{}{article}
+	h.inline.store(key, results)
+	return results
+}
@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"strings"
+
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// locale picks the reply language: the chat's stored choice, then the
+// sender's Telegram language, then the configured default.
+func (h *BotHandler) locale(chatID int64, from *tgbotapi.User) string {
+	settings := h.settings.Load()
+
+	if stored, ok := i18n.Match(h.prefService.Language(chatID), settings.Locales); ok {
+		return stored
+	}
+	if from != nil {
+		if detected, ok := i18n.Match(from.LanguageCode, settings.Locales); ok {
+			return detected
+		}
+	}
+	return settings.DefaultLocale
+}
+
+func (h *BotHandler) handleLanguage(req *Request) {
+	chatID, locale := req.ChatID, req.Locale
+	settings := h.settings.Load()
+
+	if len(req.Args) == 0 {
+		h.replyText(chatID, i18n.T(locale, i18n.LanguageCurrent, locale))
+		return
+	}
+	arg := req.Args[0]
+
+	selected, ok := i18n.Match(arg, settings.Locales)
+	if !ok {
+		h.replyText(chatID, i18n.T(locale, i18n.LanguageUnsupported, arg, strings.Join(settings.Locales, ", ")))
+		return
+	}
+
+	if err := h.prefService.SetLanguage(chatID, selected); err != nil {
+		h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
+		return
+	}
+
+	h.replyText(chatID, i18n.T(selected, i18n.LanguageChanged))
+}
@@ -0,0 +1,122 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	liveOn  = "on"
+	liveOff = "off"
+)
+
+// handleLive turns live mode on or off. In live mode scheduled updates edit
+// one pinned message instead of sending a new one each time.
+func (h *BotHandler) handleLive(req *Request) {
+	chatID, locale := req.ChatID, req.Locale
+	current := h.prefService.LiveMessage(chatID)
+
+	if req.Args[0] == liveOff {
+		if current == 0 {
+			h.replyText(chatID, i18n.T(locale, i18n.LiveNotEnabled))
+			return
+		}
+		if err := h.prefService.SetLiveMessage(chatID, 0); err != nil {
+			h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
+			return
+		}
+		h.unpin(chatID, current)
+		h.replyText(chatID, i18n.T(locale, i18n.LiveOff))
+		return
+	}
+
+	if _, err := h.userService.Get(chatID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			h.replyText(chatID, i18n.T(locale, i18n.LiveNotRegistered))
+			return
+		}
+		h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
+		return
+	}
+
+	rates, err := h.chatRates(chatID, nil)
+	if err != nil {
+		h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
+		return
+	}
+
+	if current != 0 {
+		h.unpin(chatID, current)
+	}
+	if !h.postLive(chatID, h.liveMessage(rates, locale)) {
+		h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
+		return
+	}
+	h.adminService.RecordSent(1)
+}
+
+// updateLive edits a chat's live message, re-posting it when it was deleted.
+func (h *BotHandler) updateLive(chatID int64, messageID int, text string) bool {
+	_, err := h.bot.Send(tgbotapi.NewEditMessageText(chatID, messageID, text))
+	if err == nil || isNotModified(err) {
+		return true
+	}
+	if isMessageNotFound(err) {
+		h.logger.Printf("updateLive: message %d in chat_id=%d is gone; re-posting\n", messageID, chatID)
+		return h.postLive(chatID, text)
+	}
+
+	h.sendFailed(chatID, err)
+	return false
+}
+
+// postLive sends a new live message, pins it and remembers it.
+func (h *BotHandler) postLive(chatID int64, text string) bool {
+	sent, err := h.bot.Send(tgbotapi.NewMessage(chatID, text))
+	if err != nil {
+		h.sendFailed(chatID, err)
+		return false
+	}
+
+	pin := tgbotapi.PinChatMessageConfig{ChatID: chatID, MessageID: sent.MessageID, DisableNotification: true}
+	if _, err := h.bot.Request(pin); err != nil {
+		h.logger.Printf("postLive: could not pin message in chat_id=%d: %v\n", chatID, err)
+	}
+
+	return h.prefService.SetLiveMessage(chatID, sent.MessageID) == nil
+}
+
+func (h *BotHandler) unpin(chatID int64, messageID int) {
+	unpin := tgbotapi.UnpinChatMessageConfig{ChatID: chatID, MessageID: messageID}
+	if _, err := h.bot.Request(unpin); err != nil {
+		h.logger.Printf("unpin: message %d in chat_id=%d: %v\n", messageID, chatID, err)
+	}
+}
+
+// liveMessage renders rates under a header, each marked with the direction
+// of its last change.
+func (h *BotHandler) liveMessage(rates []fetcher.Rate, locale string) string {
+	lines := []string{i18n.T(locale, i18n.LiveHeader)}
+	for i := range rates {
+		lines = append(lines, h.trends.indicator(rates[i].Code)+" "+h.rateMessage(&rates[i], locale))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isMessageNotFound(err error) bool {
+	var tgErr *tgbotapi.Error
+	return errors.As(err, &tgErr) && strings.Contains(tgErr.Message, "message to edit not found")
+}
+
+func liveArgs(raw string) ([]string, error) {
+	switch arg := strings.ToLower(strings.TrimSpace(raw)); arg {
+	case liveOn, liveOff:
+		return []string{arg}, nil
+	}
+	return nil, errBadArgs
+}
@@ -0,0 +1,36 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestLiveArgs(t *testing.T) {
+	for raw, want := range map[string]string{"on": "on", " OFF ": "off", "": "", "maybe": "", "on off": ""} {
+		args, err := liveArgs(raw)
+		if want == "" {
+			if err == nil {
+				t.Errorf("liveArgs(%q) = %v; want an error", raw, args)
+			}
+			continue
+		}
+		if err != nil || len(args) != 1 || args[0] != want {
+			t.Errorf("liveArgs(%q) = %v, %v; want [%s]", raw, args, err, want)
+		}
+	}
+}
+
+func TestEditErrors(t *testing.T) {
+	notFound := fmt.Errorf("send: %w", &tgbotapi.Error{Code: 400, Message: "Bad Request: message to edit not found"})
+	notModified := &tgbotapi.Error{Code: 400, Message: "Bad Request: message is not modified"}
+
+	if !isMessageNotFound(notFound) || isMessageNotFound(notModified) || isMessageNotFound(errors.New("timeout")) {
+		t.Error("isMessageNotFound misclassified an error")
+	}
+	if !isNotModified(notModified) || isNotModified(notFound) {
+		t.Error("isNotModified misclassified an error")
+	}
+}
@@ -2,21 +2,32 @@ package bot
 
 import (
 	"context"
-	"fmt"
 	"time"
-)
 
-const interval = time.Hour
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
 
+// startNotify runs notify now and on every tick. It is the only caller of
+// notify, so runs never overlap; /fetchnow asks it for an extra one.
 func (h *BotHandler) startNotify(ctx context.Context) {
+	interval := h.settings.Load().NotifyInterval
 	ticker := time.NewTicker(interval)
 
-	go h.notify()
+	h.notify()
 
 	for {
 		select {
 		case <-ticker.C:
 			h.notify()
+		case <-h.fetchNow:
+			h.notify()
+		case <-h.settingsChanged:
+			if next := h.settings.Load().NotifyInterval; next != interval {
+				h.logger.Printf("NotifyHandler: interval changed from %s to %s\n", interval, next)
+				interval = next
+				ticker.Reset(interval)
+			}
 		case <-ctx.Done():
 			h.logger.Println("NotifyHandler: context canceled; stopping notifications")
 			ticker.Stop()
@@ -32,15 +43,117 @@ func (h *BotHandler) notify() {
 		h.logger.Printf("NotifyHandler: failed to get users or rate: %v\n", err)
 		return
 	}
+	if rate.Stale {
+		h.logger.Println("NotifyHandler: provider unreachable; sending the last known rates")
+	}
+
+	bulletin, err := h.notifyService.Bulletin()
+	if err != nil {
+		h.logger.Printf("NotifyHandler: failed to get bulletin; sending %s only: %v\n", rate.Code, err)
+	} else {
+		h.digestService.Record(bulletin, time.Now())
+	}
+	h.trends.observe([]fetcher.Rate{*rate})
+	h.trends.observe(bulletin)
 
 	if len(ids) == 0 {
 		h.logger.Println("NotifyHandler: no subscribers; skipping send")
 		return
 	}
 
-	text := fmt.Sprintf("EUR→TRY Selling: %.4f Buying: %.4f (at %s)", rate.Selling, rate.Buying, time.Now().Format("15:04"))
+	settings := h.settings.Load()
+	languages := h.prefService.Languages()
+	subscriptions := h.subService.All()
+	live := h.prefService.LiveMessages()
+	priceTypes := h.prefService.PriceTypes()
+
+	texts := make(map[string]string)
+	sent := 0
 	for _, chatID := range ids {
-		h.replyText(chatID, text)
+		locale := settings.chatLocale(languages[chatID])
+
+		rates := pickRates(bulletin, subscriptions[chatID])
+		if len(rates) == 0 {
+			rates = []fetcher.Rate{*rate}
+		}
+		priceType := priceTypes[chatID]
+		rates = fetcher.WithPrices(rates, priceType)
+
+		messageID, isLive := live[chatID]
+
+		key := locale + "|" + priceType
+		for _, r := range rates {
+			key += "|" + r.Code.String()
+		}
+		if isLive {
+			key = "live|" + key
+		}
+		text, ok := texts[key]
+		if !ok {
+			if isLive {
+				text = h.liveMessage(rates, locale)
+			} else {
+				text = h.ratesMessage(rates, locale)
+			}
+			texts[key] = text
+		}
+
+		if isLive {
+			if h.updateLive(chatID, messageID, text) {
+				sent++
+			}
+			continue
+		}
+
+		msg := tgbotapi.NewMessage(chatID, text)
+		msg.ReplyMarkup = h.refreshKeyboard(chatID, locale, nil)
+		if h.deliver(msg) {
+			sent++
+		}
+	}
+	h.adminService.RecordSent(sent)
+	h.logger.Printf("%d/%d users have been notified.\n", sent, len(ids))
+
+	h.sendDigests(ids, languages, subscriptions)
+}
+
+func (h *BotHandler) SendRate(chatID int64) error {
+	rates, err := h.chatRates(chatID, nil)
+	if err != nil {
+		return err
+	}
+
+	locale := h.settings.Load().chatLocale(h.prefService.Language(chatID))
+	msg := tgbotapi.NewMessage(chatID, h.ratesMessage(rates, locale))
+	msg.ReplyMarkup = h.refreshKeyboard(chatID, locale, nil)
+	_, err = h.bot.Send(msg)
+	return err
+}
+
+func (h *BotHandler) Broadcast(text string) (int, error) {
+	users, err := h.userService.ListActive()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, user := range users {
+		if h.deliver(tgbotapi.NewMessage(user.ChatID, text)) {
+			sent++
+		}
+	}
+	h.adminService.RecordSent(sent)
+	h.logger.Printf("Broadcast: %d/%d users have been notified.\n", sent, len(users))
+
+	return sent, nil
+}
+
+func (h *BotHandler) rateMessage(rate *fetcher.Rate, locale string) string {
+	now := time.Now()
+	text, err := h.settings.Load().renderRate(rate, now, locale)
+	if err != nil {
+		h.logger.Printf("rateMessage: template failed, using default: %v\n", err)
+		return defaultRateMessage(rate, now, locale)
 	}
-	h.logger.Printf("%d users have been notified.\n", len(ids))
+	return text
 }
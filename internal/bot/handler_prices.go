@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"strings"
+
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+)
+
+func (h *BotHandler) handlePrices(req *Request) {
+	chatID, locale := req.ChatID, req.Locale
+
+	if len(req.Args) == 0 {
+		h.replyText(chatID, i18n.T(locale, priceTypeStatus(h.prefService.PriceType(chatID))))
+		return
+	}
+
+	if err := h.prefService.SetPriceType(chatID, req.Args[0]); err != nil {
+		h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
+		return
+	}
+	h.replyText(chatID, i18n.T(locale, priceTypeStatus(req.Args[0])))
+}
+
+func priceTypeStatus(priceType string) string {
+	if priceType == fetcher.PriceBanknote {
+		return i18n.PricesBanknote
+	}
+	return i18n.PricesForex
+}
+
+func pricesArgs(raw string) ([]string, error) {
+	switch arg := strings.ToLower(strings.TrimSpace(raw)); arg {
+	case "":
+		return nil, nil
+	case fetcher.PriceForex, fetcher.PriceBanknote:
+		return []string{arg}, nil
+	}
+	return nil, errBadArgs
+}
@@ -0,0 +1,22 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+)
+
+func TestPricesArgs(t *testing.T) {
+	for raw, want := range map[string]string{"": "", "Banknote": "banknote", " forex ": "forex"} {
+		args, err := pricesArgs(raw)
+		if err != nil || (want == "" && args != nil) || (want != "" && (len(args) != 1 || args[0] != want)) {
+			t.Errorf("pricesArgs(%q) = %v, %v; want %q", raw, args, err, want)
+		}
+	}
+	if _, err := pricesArgs("cash"); err == nil {
+		t.Error("pricesArgs(cash) accepted")
+	}
+	if priceTypeStatus("") != i18n.PricesForex || priceTypeStatus("banknote") != i18n.PricesBanknote {
+		t.Error("priceTypeStatus returned an unexpected message")
+	}
+}
@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// maxRateCodes keeps /rate answers short and the refresh button's callback
+// data within Telegram's limit.
+const maxRateCodes = 5
+
+func (h *BotHandler) handleRate(req *Request) {
+	chatID, locale := req.ChatID, req.Locale
+	args := strings.Fields(strings.Join(req.Args, " "))
+
+	codes, err := currency.ParseAll(args)
+	if err != nil {
+		h.replyText(chatID, i18n.T(locale, i18n.RateUnknownCurrency, strings.Join(args, " ")))
+		return
+	}
+
+	rates, err := h.chatRates(chatID, codes)
+	if err != nil {
+		if errors.Is(err, domain.ErrCurrencyNotFound) {
+			h.replyText(chatID, i18n.T(locale, i18n.RateUnknownCurrency, strings.Join(currency.Strings(codes), " ")))
+			return
+		}
+		h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
+		return
+	}
+
+	reply := tgbotapi.NewMessage(chatID, h.ratesMessage(rates, locale))
+	reply.ReplyMarkup = h.refreshKeyboard(chatID, locale, codes)
+	h.reply(reply)
+}
+
+func (h *BotHandler) handleSubscribe(req *Request) {
+	chatID, locale := req.ChatID, req.Locale
+	subscribed, err := h.subService.Currencies(chatID)
+	if err != nil {
+		h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
+		return
+	}
+
+	reply := tgbotapi.NewMessage(chatID, i18n.T(locale, i18n.SubscribePrompt))
+	reply.ReplyMarkup = h.subscribeKeyboard(chatID, subscribed)
+	h.reply(reply)
+}
+
+// chatRates fetches the given codes, or the chat's subscriptions when none
+// are given, falling back to DefaultCurrency, priced as the chat prefers.
+func (h *BotHandler) chatRates(chatID int64, codes []currency.Code) ([]fetcher.Rate, error) {
+	if len(codes) == 0 {
+		subscribed, err := h.subService.Currencies(chatID)
+		if err != nil {
+			return nil, err
+		}
+		codes = subscribed
+	}
+	if len(codes) == 0 {
+		codes = []currency.Code{DefaultCurrency}
+	}
+	rates, err := h.notifyService.Rates(codes)
+	if err != nil {
+		return nil, err
+	}
+	return fetcher.WithPrices(rates, h.prefService.PriceType(chatID)), nil
+}
+
+// pickRates returns the bulletin entries for codes, in the order of codes.
+func pickRates(bulletin []fetcher.Rate, codes []currency.Code) []fetcher.Rate {
+	var rates []fetcher.Rate
+	for _, code := range codes {
+		if i := slices.IndexFunc(bulletin, func(r fetcher.Rate) bool { return r.Code == code }); i >= 0 {
+			rates = append(rates, bulletin[i])
+		}
+	}
+	return rates
+}
+
+func (h *BotHandler) ratesMessage(rates []fetcher.Rate, locale string) string {
+	lines := make([]string, 0, len(rates))
+	for i := range rates {
+		lines = append(lines, h.rateMessage(&rates[i], locale))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -4,20 +4,25 @@ import (
 	"errors"
 
 	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
 )
 
-func (h *BotHandler) handleRegister(chatID int64) {
-	err := h.userService.Register(chatID)
+func (h *BotHandler) handleRegister(req *Request) {
+	chatID, locale := req.ChatID, req.Locale
+	err := h.userService.Register(chatID, senderID(req.Message))
 
 	if err != nil {
 		if errors.Is(err, domain.ErrUserAlreadyExists) {
-			h.replyText(chatID, "You are already registered!")
+			h.replyText(chatID, i18n.T(locale, i18n.RegisterAlready))
 			return
 		}
 		h.logger.Printf("handleRegister error for chat_id=%d, error: %v\n", chatID, err)
-		h.replyText(chatID, "An unexpected error occured. Please try again later.")
+		h.replyText(chatID, i18n.T(locale, i18n.ErrorGeneric))
 		return
 	}
 
-	h.replyText(chatID, "✅ You have been registered! You will receive hourly EUR→TRY updates.")
+	// Notifications have no sender to take the language from, so keep the
+	// one the chat registered with.
+	h.prefService.InitLanguage(chatID, locale)
+	h.replyText(chatID, i18n.T(locale, i18n.RegisterSuccess))
 }
@@ -0,0 +1,216 @@
+package bot
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+)
+
+const (
+	BaseCurrency = currency.TRY
+
+	// inlineBulletinTTL bounds how often typing in inline mode hits the
+	// provider; inlineCacheTime is how long Telegram may reuse an answer.
+	inlineBulletinTTL = 5 * time.Minute
+	inlineCacheTime   = 300
+
+	// maxInlineAnswers bounds the answer cache, whose keys include the
+	// amount users type.
+	maxInlineAnswers = 1000
+)
+
+// maxAmount keeps conversions far from the range of decimal.Decimal.
+var maxAmount = decimal.FromInt(1_000_000_000_000)
+
+var errInvalidQuery = errors.New("invalid inline query")
+
+// conversion is a parsed inline query: "100 eur", "eur try", "usd".
+type conversion struct {
+	Amount decimal.Decimal
+	From   currency.Code
+	To     currency.Code
+}
+
+func parseConversion(query string) (conversion, error) {
+	c := conversion{Amount: decimal.FromInt(1), To: BaseCurrency}
+
+	fields := strings.Fields(query)
+	if len(fields) > 0 {
+		if amount, ok := parseAmount(fields[0]); ok {
+			c.Amount = amount
+			fields = fields[1:]
+		}
+	}
+
+	var err error
+	switch len(fields) {
+	case 0:
+		c.From = DefaultCurrency
+	case 1:
+		c.From, err = currency.Parse(fields[0])
+	case 2:
+		if c.From, err = currency.Parse(fields[0]); err == nil {
+			c.To, err = currency.Parse(fields[1])
+		}
+	default:
+		return c, errInvalidQuery
+	}
+
+	if err != nil || c.From == c.To {
+		return c, errInvalidQuery
+	}
+	return c, nil
+}
+
+// parseAmount accepts a decimal point or a decimal comma.
+func parseAmount(s string) (decimal.Decimal, bool) {
+	amount, err := decimal.Parse(strings.Replace(s, ",", ".", 1))
+	if err != nil || amount.Sign() <= 0 || amount.Cmp(maxAmount) > 0 {
+		return decimal.Decimal{}, false
+	}
+	return amount, true
+}
+
+// convert prices c against the bulletin, which quotes every currency in
+// TRY. Foreign currency is sold to the bank at its buying rate and bought at
+// its selling rate. The result is rounded half to even once, after both
+// legs of the conversion.
+func convert(bulletin []fetcher.Rate, c conversion) (decimal.Decimal, fetcher.Rate, fetcher.Rate, error) {
+	from, ok := findRate(bulletin, c.From)
+	if !ok {
+		return decimal.Decimal{}, from, fetcher.Rate{}, fmt.Errorf("%s: %w", c.From, errInvalidQuery)
+	}
+	to, ok := findRate(bulletin, c.To)
+	if !ok {
+		return decimal.Decimal{}, from, to, fmt.Errorf("%s: %w", c.To, errInvalidQuery)
+	}
+
+	if to.Selling.Sign() <= 0 {
+		return decimal.Decimal{}, from, to, fmt.Errorf("%s has no selling rate: %w", c.To, errInvalidQuery)
+	}
+
+	// amount * (buying / fromUnit) / (selling / toUnit)
+	buying, err := from.Buying.MulInt(unit(to))
+	if err != nil {
+		return decimal.Decimal{}, from, to, fmt.Errorf("%w: %w", err, errInvalidQuery)
+	}
+	selling, err := to.Selling.MulInt(unit(from))
+	if err != nil {
+		return decimal.Decimal{}, from, to, fmt.Errorf("%w: %w", err, errInvalidQuery)
+	}
+	value, err := decimal.MulDiv(c.Amount, buying, selling, decimal.RoundHalfEven)
+	if err != nil {
+		return decimal.Decimal{}, from, to, fmt.Errorf("%w: %w", err, errInvalidQuery)
+	}
+	return value, from, to, nil
+}
+
+func findRate(bulletin []fetcher.Rate, code currency.Code) (fetcher.Rate, bool) {
+	if code == BaseCurrency {
+		one := decimal.FromInt(1)
+		return fetcher.Rate{Code: BaseCurrency, Unit: 1, Buying: one, Selling: one}, true
+	}
+	i := slices.IndexFunc(bulletin, func(r fetcher.Rate) bool { return r.Code == code })
+	if i < 0 {
+		return fetcher.Rate{}, false
+	}
+	return bulletin[i], true
+}
+
+// unit is the number of units r is quoted for, at least one.
+func unit(r fetcher.Rate) int64 {
+	return int64(max(r.Unit, 1))
+}
+
+// inlineCache keeps the last bulletin for inlineBulletinTTL and the answers
+// computed from it; answers are dropped when the bulletin changes, and the
+// least recently used ones beyond maxInlineAnswers.
+type inlineCache struct {
+	mu          sync.Mutex
+	bulletin    []fetcher.Rate
+	fetchedAt   time.Time
+	fingerprint string
+	answers     map[string]*list.Element
+	// order holds the answers, most recently used first.
+	order *list.List
+}
+
+type inlineAnswer struct {
+	key     string
+	results []interface{}
+}
+
+func newInlineCache() *inlineCache {
+	return &inlineCache{answers: make(map[string]*list.Element), order: list.New()}
+}
+
+func (c *inlineCache) load(now time.Time, fetch func() ([]fetcher.Rate, error)) ([]fetcher.Rate, time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.bulletin != nil && now.Sub(c.fetchedAt) < inlineBulletinTTL {
+		return c.bulletin, c.fetchedAt, nil
+	}
+
+	bulletin, err := fetch()
+	if err != nil {
+		if c.bulletin != nil {
+			return c.bulletin, c.fetchedAt, nil
+		}
+		return nil, time.Time{}, err
+	}
+
+	if fp := fingerprint(bulletin); fp != c.fingerprint {
+		c.fingerprint = fp
+		c.answers = make(map[string]*list.Element)
+		c.order.Init()
+	}
+	c.bulletin = bulletin
+	c.fetchedAt = now
+	return bulletin, now, nil
+}
+
+func (c *inlineCache) answer(key string) ([]interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.answers[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*inlineAnswer).results, true
+}
+
+func (c *inlineCache) store(key string, results []interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.answers[key]; ok {
+		e.Value.(*inlineAnswer).results = results
+		c.order.MoveToFront(e)
+		return
+	}
+	c.answers[key] = c.order.PushFront(&inlineAnswer{key: key, results: results})
+	if c.order.Len() > maxInlineAnswers {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.answers, oldest.Value.(*inlineAnswer).key)
+	}
+}
+
+func fingerprint(bulletin []fetcher.Rate) string {
+	h := sha256.New()
+	for _, r := range bulletin {
+		fmt.Fprintf(h, "%s|%d|%s|%s\n", r.Code, r.Unit, r.Buying, r.Selling)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,174 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestParseConversion(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected conversion
+		wantErr  bool
+	}{
+		{query: "", expected: conversion{Amount: decimal.FromInt(1), From: "EUR", To: "TRY"}},
+		{query: "usd", expected: conversion{Amount: decimal.FromInt(1), From: "USD", To: "TRY"}},
+		{query: "100 eur", expected: conversion{Amount: decimal.FromInt(100), From: "EUR", To: "TRY"}},
+		{query: "eur try", expected: conversion{Amount: decimal.FromInt(1), From: "EUR", To: "TRY"}},
+		{query: " 12,5  Try  usd ", expected: conversion{Amount: decimal.MustParse("12.5"), From: "TRY", To: "USD"}},
+		{query: "100 eur usd", expected: conversion{Amount: decimal.FromInt(100), From: "EUR", To: "USD"}},
+		{query: "eur eur", wantErr: true},
+		{query: "100 euro", expected: conversion{Amount: decimal.FromInt(100), From: "EUR", To: "TRY"}},
+		{query: "50 € $", expected: conversion{Amount: decimal.FromInt(50), From: "EUR", To: "USD"}},
+		{query: "1 altın", expected: conversion{Amount: decimal.FromInt(1), From: "GAU", To: "TRY"}},
+		{query: "euro avro", wantErr: true},
+		{query: "100 eu", wantErr: true},
+		{query: "-5 eur", wantErr: true},
+		{query: "2000000000000 eur", wantErr: true},
+		{query: "1 eur usd gbp", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			got, err := parseConversion(tc.query)
+			if tc.wantErr {
+				if !errors.Is(err, errInvalidQuery) {
+					t.Fatalf("parseConversion(%q) error = %v; want %v", tc.query, err, errInvalidQuery)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseConversion(%q): %v", tc.query, err)
+			}
+			if got != tc.expected {
+				t.Errorf("parseConversion(%q) = %+v; want %+v", tc.query, got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	bulletin := []fetcher.Rate{
+		{Code: "USD", Unit: 1, Buying: decimal.FromInt(34), Selling: decimal.FromInt(35)},
+		{Code: "EUR", Unit: 1, Buying: decimal.FromInt(36), Selling: decimal.FromInt(37)},
+		{Code: "JPY", Unit: 100, Buying: decimal.FromInt(22), Selling: decimal.FromInt(23)},
+	}
+
+	tests := []struct {
+		name     string
+		c        conversion
+		expected string
+		wantErr  bool
+	}{
+		{name: "ToTRY", c: conversion{Amount: decimal.FromInt(100), From: "EUR", To: "TRY"}, expected: "3600"},
+		{name: "FromTRY", c: conversion{Amount: decimal.FromInt(70), From: "TRY", To: "USD"}, expected: "2"},
+		{name: "Cross", c: conversion{Amount: decimal.FromInt(35), From: "EUR", To: "USD"}, expected: "36"},
+		{name: "Unit100", c: conversion{Amount: decimal.FromInt(1000), From: "JPY", To: "TRY"}, expected: "220"},
+		{name: "Rounded", c: conversion{Amount: decimal.FromInt(10), From: "USD", To: "EUR"}, expected: "9.189189"},
+		{name: "Unknown", c: conversion{Amount: decimal.FromInt(1), From: "XYZ", To: "TRY"}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, _, _, err := convert(bulletin, tc.c)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("convert: %v", err)
+			}
+			if got.String() != tc.expected {
+				t.Errorf("convert = %v; want %v", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestInlineCache(t *testing.T) {
+	cache := newInlineCache()
+	now := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+	rates := []fetcher.Rate{{Code: "EUR", Buying: decimal.FromInt(36)}}
+	calls := 0
+	fetch := func() ([]fetcher.Rate, error) {
+		calls++
+		return rates, nil
+	}
+
+	if _, _, err := cache.load(now, fetch); err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	cache.store("q", []interface{}{"answer"})
+
+	cache.load(now.Add(time.Minute), fetch)
+	if calls != 1 {
+		t.Errorf("fetch calls = %d; want 1 within TTL", calls)
+	}
+
+	cache.load(now.Add(inlineBulletinTTL), fetch)
+	if _, ok := cache.answer("q"); !ok || calls != 2 {
+		t.Errorf("unchanged bulletin should keep answers (calls=%d)", calls)
+	}
+
+	rates = []fetcher.Rate{{Code: "EUR", Buying: decimal.FromInt(37)}}
+	cache.load(now.Add(2*inlineBulletinTTL), fetch)
+	if _, ok := cache.answer("q"); ok {
+		t.Errorf("changed bulletin should drop answers")
+	}
+
+	_, fetchedAt, err := cache.load(now.Add(3*inlineBulletinTTL), func() ([]fetcher.Rate, error) {
+		return nil, errors.New("down")
+	})
+	if err != nil || !fetchedAt.Equal(now.Add(2*inlineBulletinTTL)) {
+		t.Errorf("failed refresh should serve the cached bulletin, got %v %v", fetchedAt, err)
+	}
+}
+
+func TestInlineCacheEvicts(t *testing.T) {
+	cache := newInlineCache()
+	for i := range maxInlineAnswers {
+		cache.store(fmt.Sprint(i), []interface{}{i})
+	}
+	cache.answer("0")
+	cache.store("new", []interface{}{"answer"})
+
+	if len(cache.answers) != maxInlineAnswers || cache.order.Len() != maxInlineAnswers {
+		t.Fatalf("cache holds %d answers; want %d", len(cache.answers), maxInlineAnswers)
+	}
+	if _, ok := cache.answer("1"); ok {
+		t.Errorf("least recently used answer should be evicted")
+	}
+	if _, ok := cache.answer("0"); !ok {
+		t.Errorf("recently read answer should be kept")
+	}
+}
+
+func TestInlineResults(t *testing.T) {
+	h := &BotHandler{inline: newInlineCache(), logger: discardLogger}
+	bulletin := []fetcher.Rate{{
+		Code: "JPY", Unit: 100, Buying: decimal.MustParse("22.4361"), Selling: decimal.MustParse("22.5848"),
+		Source: fetcher.SourceFile, Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC),
+	}}
+	h.inline.load(time.Now(), func() ([]fetcher.Rate, error) { return bulletin, nil })
+
+	results := h.inlineResults("1.2345 jpy", "en", fetcher.PriceForex)
+	if len(results) != 1 {
+		t.Fatalf("results = %v", results)
+	}
+	article := results[0].(tgbotapi.InlineQueryResultArticle)
+	text := article.InputMessageContent.(tgbotapi.InputTextMessageContent).Text
+	for _, want := range []string{"1.2345 JPY = ", "100 JPY buying 22.4361, selling 22.5848", "Rates of 2025-01-02"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text = %q; want it to contain %q", text, want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"slices"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	DefaultCurrency = currency.EUR
+
+	keyboardColumns = 4
+)
+
+// SubscribableCurrencies are the bulletin currencies offered by /subscribe,
+// before the codes of extra providers.
+var SubscribableCurrencies = []currency.Code{
+	"USD", "EUR", "GBP", "CHF",
+	"JPY", "CAD", "AUD", "SEK",
+	"NOK", "DKK", "SAR", "RUB",
+	"CNY", "AED", "KWD", "QAR",
+}
+
+func (h *BotHandler) subscribeKeyboard(chatID int64, subscribed []currency.Code) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, code := range h.settings.Load().Currencies {
+		label := code.String()
+		if c, ok := currency.Lookup(code); ok && c.Flag != "" {
+			label = c.Flag + " " + label
+		}
+		if slices.Contains(subscribed, code) {
+			label = "✅ " + label
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, h.callbacks.encode(chatID, cbSubscribe, code.String())))
+		if len(row) == keyboardColumns {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func (h *BotHandler) deleteKeyboard(chatID int64, locale string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(locale, i18n.ButtonYes), h.callbacks.encode(chatID, cbDelete, "y")),
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(locale, i18n.ButtonNo), h.callbacks.encode(chatID, cbDelete, "n")),
+	))
+}
+
+// refreshKeyboard re-fetches codes on tap; without codes it follows the
+// chat's subscriptions.
+func (h *BotHandler) refreshKeyboard(chatID int64, locale string, codes []currency.Code) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(i18n.T(locale, i18n.ButtonRefresh), h.callbacks.encode(chatID, cbRefresh, currency.Strings(codes)...)),
+	))
+}
@@ -0,0 +1,11 @@
+package bot
+
+import "expvar"
+
+// Exported through expvar; cmd/currency-bot serves them on http.metrics_addr.
+var (
+	commandsTotal       = expvar.NewMap("bot_commands_total")
+	commandDurationMs   = expvar.NewMap("bot_command_duration_ms_total")
+	commandsRateLimited = expvar.NewInt("bot_commands_rate_limited_total")
+	handlerPanics       = expvar.NewInt("bot_handler_panics_total")
+)
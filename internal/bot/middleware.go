@@ -0,0 +1,125 @@
+package bot
+
+import (
+	"runtime/debug"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	"github.com/akyTheDev/currency-bot/internal/models"
+	"github.com/akyTheDev/currency-bot/internal/ratelimit"
+)
+
+func commandName(req *Request) string {
+	if req.Command == nil {
+		return "unknown"
+	}
+	return req.Command.Name
+}
+
+func (h *BotHandler) recoverPanics(next HandlerFunc) HandlerFunc {
+	return func(req *Request) {
+		defer func() {
+			if r := recover(); r != nil {
+				handlerPanics.Add(1)
+				h.logger.Printf("PANIC in /%s for chat_id=%d: %v\n%s", commandName(req), req.ChatID, r, debug.Stack())
+
+				locale := req.Locale
+				if locale == "" {
+					locale = h.settings.Load().DefaultLocale
+				}
+				h.replyText(req.ChatID, i18n.T(locale, i18n.ErrorGeneric))
+			}
+		}()
+		next(req)
+	}
+}
+
+func (h *BotHandler) logCommands(next HandlerFunc) HandlerFunc {
+	return func(req *Request) {
+		start := time.Now()
+		h.logger.Printf("Received command: %s from chat_id=%d\n", req.Message.Command(), req.ChatID)
+		next(req)
+		h.logger.Printf("Handled command: %s for chat_id=%d in %s\n", req.Message.Command(), req.ChatID, time.Since(start))
+	}
+}
+
+func (h *BotHandler) countCommands(next HandlerFunc) HandlerFunc {
+	return func(req *Request) {
+		start := time.Now()
+		next(req)
+		name := commandName(req)
+		commandsTotal.Add(name, 1)
+		commandDurationMs.Add(name, time.Since(start).Milliseconds())
+	}
+}
+
+func (h *BotHandler) resolveLocale(next HandlerFunc) HandlerFunc {
+	return func(req *Request) {
+		req.Locale = h.locale(req.ChatID, req.Message.From)
+		next(req)
+	}
+}
+
+// limitRate answers the first command over a chat's budget with a warning
+// and drops the rest silently until the bucket refills. Limiter errors let
+// the command through. It runs before resolveLocale, so throttled commands
+// cost no preference lookup unless the warning is sent.
+func (h *BotHandler) limitRate(next HandlerFunc) HandlerFunc {
+	return func(req *Request) {
+		decision, err := h.limiter.Take(req.ChatID)
+		if err != nil {
+			h.logger.Printf("Rate limiter error for chat_id=%d: %v\n", req.ChatID, err)
+		}
+
+		switch decision {
+		case ratelimit.Warn:
+			req.Throttled = true
+			commandsRateLimited.Add(1)
+			h.logger.Printf("Rate limited command: %s from chat_id=%d\n", req.Message.Command(), req.ChatID)
+			h.replyText(req.ChatID, i18n.T(h.locale(req.ChatID, req.Message.From), i18n.SlowDown))
+		case ratelimit.Drop:
+			req.Throttled = true
+			commandsRateLimited.Add(1)
+		default:
+			next(req)
+		}
+	}
+}
+
+// authorize refuses admin commands from non-admins and audits every admin
+// command attempt.
+func (h *BotHandler) authorize(next HandlerFunc) HandlerFunc {
+	return func(req *Request) {
+		if req.Command == nil || !req.Command.Admin {
+			next(req)
+			return
+		}
+
+		msg := req.Message
+		userID := senderID(msg)
+		if !h.isAdmin(userID) {
+			h.logger.Printf("AUDIT: unauthorized command=/%s user_id=%d chat_id=%d\n", msg.Command(), userID, req.ChatID)
+			h.adminService.RecordAction(models.AuditUnauthorized, userID, req.ChatID, msg.Command(), msg.CommandArguments())
+			h.replyText(req.ChatID, i18n.T(req.Locale, i18n.AdminUnauthorized))
+			return
+		}
+
+		h.logger.Printf("AUDIT: admin command=/%s user_id=%d chat_id=%d args=%q\n", msg.Command(), userID, req.ChatID, msg.CommandArguments())
+		h.adminService.RecordAction(models.AuditAdminAction, userID, req.ChatID, msg.Command(), msg.CommandArguments())
+		next(req)
+	}
+}
+
+// requireGroupAdmin refuses GroupAdmin commands from members of a group who
+// are not its administrators.
+func (h *BotHandler) requireGroupAdmin(next HandlerFunc) HandlerFunc {
+	return func(req *Request) {
+		if req.Command == nil || !req.Command.GroupAdmin || h.isSenderChatAdmin(req.Message) {
+			next(req)
+			return
+		}
+
+		h.logger.Printf("Refused /%s from non-admin in group chat_id=%d\n", req.Command.Name, req.ChatID)
+		h.replyText(req.ChatID, i18n.T(req.Locale, i18n.GroupAdminOnly))
+	}
+}
@@ -0,0 +1,142 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// offsetTracker follows updates handed to the worker pool. Workers finish
+// out of order, so the safe resume point is the highest update_id below
+// which every update is done.
+type offsetTracker struct {
+	mu        sync.Mutex
+	pending   map[int]struct{}
+	highest   int
+	committed int
+	saved     int
+	// handled holds the updates past the stored offset that the previous
+	// run already handled, so redeliveries are skipped without a query.
+	handled map[int]struct{}
+}
+
+func newOffsetTracker(committed int, handled []int) *offsetTracker {
+	t := &offsetTracker{
+		pending:   make(map[int]struct{}),
+		highest:   committed,
+		committed: committed,
+		saved:     committed,
+		handled:   make(map[int]struct{}, len(handled)),
+	}
+	for _, id := range handled {
+		t.handled[id] = struct{}{}
+	}
+	return t
+}
+
+func (t *offsetTracker) begin(updateID int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending[updateID] = struct{}{}
+	t.highest = max(t.highest, updateID)
+}
+
+// done marks updateID finished and returns the new resume point, with false
+// if it did not move.
+func (t *offsetTracker) done(updateID int) (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.pending, updateID)
+	t.highest = max(t.highest, updateID)
+
+	watermark := t.highest
+	for id := range t.pending {
+		watermark = min(watermark, id-1)
+	}
+	if watermark <= t.committed {
+		return t.committed, false
+	}
+	t.committed = watermark
+	return watermark, true
+}
+
+// handledBefore reports, once, whether the previous run handled updateID.
+func (t *offsetTracker) handledBefore(updateID int) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.handled[updateID]; !ok {
+		return false
+	}
+	delete(t.handled, updateID)
+	return true
+}
+
+// unsaved returns the resume point when it moved since the last call.
+func (t *offsetTracker) unsaved() (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.committed <= t.saved {
+		return t.saved, false
+	}
+	t.saved = t.committed
+	return t.committed, true
+}
+
+const (
+	pruneUpdatesInterval = time.Hour
+	// saveOffsetInterval batches offset writes; updates redelivered after a
+	// crash are still skipped through the processed update IDs.
+	saveOffsetInterval = 5 * time.Second
+)
+
+// once wraps an update handler so an update redelivered after a restart is
+// not handled twice. handle reports whether the update was acted on;
+// throttled commands are not remembered.
+func (h *BotHandler) once(updateID int, handle func() bool) func() {
+	return func() {
+		defer h.offsets.done(updateID)
+		if h.offsets.handledBefore(updateID) {
+			h.logger.Printf("Skipping already processed update_id=%d\n", updateID)
+			return
+		}
+		if handle() {
+			h.updateService.MarkProcessed(updateID)
+		}
+	}
+}
+
+func (h *BotHandler) saveOffset() {
+	if offset, moved := h.offsets.unsaved(); moved {
+		h.updateService.SaveOffset(offset)
+	}
+}
+
+// saveOffsets stores the resume point every saveOffsetInterval until ctx
+// ends; Start saves the final one once the workers are drained.
+func (h *BotHandler) saveOffsets(ctx context.Context) {
+	ticker := time.NewTicker(saveOffsetInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.saveOffset()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (h *BotHandler) pruneUpdates(ctx context.Context) {
+	ticker := time.NewTicker(pruneUpdatesInterval)
+	defer ticker.Stop()
+
+	for {
+		h.updateService.Prune()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package bot
+
+import "testing"
+
+func TestOffsetTracker(t *testing.T) {
+	tr := newOffsetTracker(10, nil)
+
+	tr.begin(11)
+	tr.begin(12)
+	tr.begin(13)
+
+	if _, moved := tr.done(12); moved {
+		t.Error("done(12) moved the offset while 11 is pending")
+	}
+	if offset, moved := tr.done(11); !moved || offset != 12 {
+		t.Errorf("done(11) = %d, %v; want 12, true", offset, moved)
+	}
+	if offset, moved := tr.done(14); moved {
+		t.Errorf("done(14) = %d, %v; want no move while 13 is pending", offset, moved)
+	}
+	if offset, moved := tr.done(13); !moved || offset != 14 {
+		t.Errorf("done(13) = %d, %v; want 14, true", offset, moved)
+	}
+	if _, moved := tr.done(9); moved {
+		t.Error("done of an old update moved the offset")
+	}
+}
+
+func TestOffsetTrackerSaves(t *testing.T) {
+	tr := newOffsetTracker(10, []int{12})
+
+	if _, moved := tr.unsaved(); moved {
+		t.Error("unsaved before any update moved")
+	}
+	if !tr.handledBefore(12) || tr.handledBefore(12) || tr.handledBefore(11) {
+		t.Error("handledBefore should report update 12 once and nothing else")
+	}
+
+	tr.begin(11)
+	tr.begin(12)
+	tr.done(11)
+	tr.done(12)
+	if offset, moved := tr.unsaved(); !moved || offset != 12 {
+		t.Errorf("unsaved = %d, %v; want 12, true", offset, moved)
+	}
+	if _, moved := tr.unsaved(); moved {
+		t.Error("unsaved moved twice for the same offset")
+	}
+}
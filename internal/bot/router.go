@@ -0,0 +1,170 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var errBadArgs = errors.New("invalid arguments")
+
+// Request is a command being handled. Middleware may fill in fields such as
+// Locale before the command's handler runs.
+type Request struct {
+	Message *tgbotapi.Message
+	// Command is nil for commands the router doesn't know.
+	Command *Command
+	ChatID  int64
+	Locale  string
+	Args    []string
+	// Throttled is set by middleware that drops the command for its rate.
+	Throttled bool
+}
+
+type HandlerFunc func(req *Request)
+
+type Middleware func(next HandlerFunc) HandlerFunc
+
+// ArgParser validates and splits the raw text after the command.
+type ArgParser func(raw string) ([]string, error)
+
+type Command struct {
+	Name string
+	// Description and Usage are i18n keys; Usage is sent when Args rejects
+	// the arguments.
+	Description string
+	Usage       string
+	Args        ArgParser
+	Admin       bool
+	// GroupAdmin limits the command to chat administrators in groups.
+	GroupAdmin bool
+	Handle     HandlerFunc
+}
+
+type Router struct {
+	commands   map[string]*Command
+	order      []*Command
+	middleware []Middleware
+	// NotFound handles unknown commands and BadArgs rejected arguments.
+	NotFound HandlerFunc
+	BadArgs  HandlerFunc
+	// Username is the bot's own username; commands addressed to another bot
+	// (/register@otherbot) are ignored.
+	Username string
+}
+
+func NewRouter() *Router {
+	return &Router{commands: make(map[string]*Command)}
+}
+
+// Use appends middleware; the first one added runs outermost.
+func (r *Router) Use(middleware ...Middleware) {
+	r.middleware = append(r.middleware, middleware...)
+}
+
+func (r *Router) Handle(cmd Command) {
+	if _, ok := r.commands[cmd.Name]; ok {
+		panic("bot: duplicate command /" + cmd.Name)
+	}
+	r.commands[cmd.Name] = &cmd
+	r.order = append(r.order, &cmd)
+}
+
+func (r *Router) Lookup(name string) (*Command, bool) {
+	cmd, ok := r.commands[name]
+	return cmd, ok
+}
+
+// Dispatch runs msg through the middleware and its command, and reports
+// whether it was acted on: false for commands addressed to other bots and
+// throttled ones.
+func (r *Router) Dispatch(msg *tgbotapi.Message) bool {
+	if _, to, ok := strings.Cut(msg.CommandWithAt(), "@"); ok && !strings.EqualFold(to, r.Username) {
+		return false
+	}
+
+	req := &Request{Message: msg, ChatID: msg.Chat.ID}
+	if cmd, ok := r.commands[msg.Command()]; ok {
+		req.Command = cmd
+	}
+
+	handler := r.invoke
+	for i := len(r.middleware) - 1; i >= 0; i-- {
+		handler = r.middleware[i](handler)
+	}
+	handler(req)
+	return !req.Throttled
+}
+
+func (r *Router) invoke(req *Request) {
+	cmd := req.Command
+	if cmd == nil {
+		if r.NotFound != nil {
+			r.NotFound(req)
+		}
+		return
+	}
+
+	raw := req.Message.CommandArguments()
+	if cmd.Args == nil {
+		req.Args = strings.Fields(raw)
+	} else {
+		args, err := cmd.Args(raw)
+		if err != nil {
+			if r.BadArgs != nil {
+				r.BadArgs(req)
+			}
+			return
+		}
+		req.Args = args
+	}
+
+	cmd.Handle(req)
+}
+
+// Commands lists the non-admin commands for SetMyCommands.
+func (r *Router) Commands(locale string) []tgbotapi.BotCommand {
+	var commands []tgbotapi.BotCommand
+	for _, cmd := range r.order {
+		if cmd.Admin {
+			continue
+		}
+		commands = append(commands, tgbotapi.BotCommand{Command: cmd.Name, Description: i18n.T(locale, cmd.Description)})
+	}
+	return commands
+}
+
+// Help renders one line per command, including admin commands only when
+// admin is set.
+func (r *Router) Help(locale string, admin bool) string {
+	lines := []string{i18n.T(locale, i18n.HelpHeader)}
+	for _, cmd := range r.order {
+		if cmd.Admin && !admin {
+			continue
+		}
+		lines = append(lines, "/"+cmd.Name+" — "+i18n.T(locale, cmd.Description))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// MaxArgs accepts up to n whitespace separated arguments.
+func MaxArgs(n int) ArgParser {
+	return func(raw string) ([]string, error) {
+		args := strings.Fields(raw)
+		if len(args) > n {
+			return nil, errBadArgs
+		}
+		return args, nil
+	}
+}
+
+// Text passes the whole argument string as a single, required argument.
+func Text(raw string) ([]string, error) {
+	text := strings.TrimSpace(raw)
+	if text == "" {
+		return nil, errBadArgs
+	}
+	return []string{text}, nil
+}
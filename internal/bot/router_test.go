@@ -0,0 +1,105 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func command(text string) *tgbotapi.Message {
+	name, _, _ := strings.Cut(strings.TrimPrefix(text, "/"), " ")
+	return &tgbotapi.Message{
+		Text:     text,
+		Chat:     &tgbotapi.Chat{ID: 42},
+		Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(name) + 1}},
+	}
+}
+
+func TestRouterDispatch(t *testing.T) {
+	var trace []string
+	trace1 := func(name string) Middleware {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(req *Request) {
+				trace = append(trace, name)
+				next(req)
+			}
+		}
+	}
+
+	var got *Request
+	r := NewRouter()
+	r.Username = "CurrencyBot"
+	r.Use(trace1("outer"), trace1("inner"))
+	r.NotFound = func(req *Request) { trace = append(trace, "notfound") }
+	r.BadArgs = func(req *Request) { trace = append(trace, "badargs") }
+	r.Handle(Command{Name: "rate", Args: MaxArgs(2), Handle: func(req *Request) { got = req }})
+	r.Handle(Command{Name: "say", Args: Text, Handle: func(req *Request) { got = req }})
+
+	tests := []struct {
+		text      string
+		wantTrace string
+		wantArgs  []string
+	}{
+		{text: "/rate usd eur", wantTrace: "outer,inner", wantArgs: []string{"usd", "eur"}},
+		{text: "/rate a b c", wantTrace: "outer,inner,badargs"},
+		{text: "/say hello  world", wantTrace: "outer,inner", wantArgs: []string{"hello  world"}},
+		{text: "/say", wantTrace: "outer,inner,badargs"},
+		{text: "/nope", wantTrace: "outer,inner,notfound"},
+		{text: "/rate@currencybot usd", wantTrace: "outer,inner", wantArgs: []string{"usd"}},
+		{text: "/rate@otherbot usd", wantTrace: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.text, func(t *testing.T) {
+			trace, got = nil, nil
+			if acted := r.Dispatch(command(tc.text)); acted != (tc.wantTrace != "") {
+				t.Errorf("Dispatch = %v for trace %q", acted, tc.wantTrace)
+			}
+
+			if strings.Join(trace, ",") != tc.wantTrace {
+				t.Errorf("trace = %v; want %s", trace, tc.wantTrace)
+			}
+			if tc.wantArgs == nil {
+				if got != nil {
+					t.Errorf("handler ran with %v", got.Args)
+				}
+				return
+			}
+			if got == nil || strings.Join(got.Args, "|") != strings.Join(tc.wantArgs, "|") || got.ChatID != 42 {
+				t.Errorf("request = %+v; want args %v", got, tc.wantArgs)
+			}
+		})
+	}
+}
+
+func TestRouterDispatchThrottled(t *testing.T) {
+	r := NewRouter()
+	r.Use(func(next HandlerFunc) HandlerFunc {
+		return func(req *Request) { req.Throttled = true }
+	})
+	r.Handle(Command{Name: "rate", Handle: func(*Request) { t.Error("throttled command ran") }})
+
+	if r.Dispatch(command("/rate")) {
+		t.Error("Dispatch = true for a throttled command")
+	}
+}
+
+func TestRouterCommandsAndHelp(t *testing.T) {
+	r := NewRouter()
+	noop := func(*Request) {}
+	r.Handle(Command{Name: "register", Description: "help.register", Handle: noop})
+	r.Handle(Command{Name: "stats", Description: "help.stats", Admin: true, Handle: noop})
+
+	commands := r.Commands("en")
+	if len(commands) != 1 || commands[0].Command != "register" || commands[0].Description == "help.register" {
+		t.Errorf("Commands = %+v; want only a described /register", commands)
+	}
+
+	if help := r.Help("en", false); strings.Contains(help, "/stats") || !strings.Contains(help, "/register") {
+		t.Errorf("Help(non-admin) = %q", help)
+	}
+	if help := r.Help("en", true); !strings.Contains(help, "/stats") {
+		t.Errorf("Help(admin) = %q; want /stats", help)
+	}
+}
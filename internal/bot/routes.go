@@ -0,0 +1,76 @@
+package bot
+
+import (
+	"strings"
+
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+)
+
+const CmdHelp = "help"
+
+func (h *BotHandler) newRouter() *Router {
+	r := NewRouter()
+	r.Use(h.recoverPanics, h.logCommands, h.countCommands, h.limitRate, h.resolveLocale, h.authorize, h.requireGroupAdmin)
+	r.NotFound = h.handleUnknown
+	r.BadArgs = h.handleBadArgs
+	r.Username = h.bot.Self.UserName
+
+	r.Handle(Command{Name: CmdRegister, Description: i18n.HelpRegister, GroupAdmin: true, Handle: h.handleRegister})
+	r.Handle(Command{Name: CmdSubscribe, Description: i18n.HelpSubscribe, GroupAdmin: true, Handle: h.handleSubscribe})
+	r.Handle(Command{
+		Name: CmdRate, Description: i18n.HelpRate, Usage: i18n.UsageRate,
+		Args: MaxArgs(maxRateCodes), Handle: h.handleRate,
+	})
+	r.Handle(Command{Name: CmdDelete, Description: i18n.HelpDelete, GroupAdmin: true, Handle: h.handleDelete})
+	r.Handle(Command{
+		Name: CmdLanguage, Description: i18n.HelpLanguage, Usage: i18n.UsageLanguage,
+		Args: MaxArgs(1), GroupAdmin: true, Handle: h.handleLanguage,
+	})
+	r.Handle(Command{
+		Name: CmdLive, Description: i18n.HelpLive, Usage: i18n.UsageLive,
+		Args: liveArgs, GroupAdmin: true, Handle: h.handleLive,
+	})
+	r.Handle(Command{
+		Name: CmdDigest, Description: i18n.HelpDigest, Usage: i18n.UsageDigest,
+		Args: digestArgs, GroupAdmin: true, Handle: h.handleDigest,
+	})
+	r.Handle(Command{
+		Name: CmdPrices, Description: i18n.HelpPrices, Usage: i18n.UsagePrices,
+		Args: pricesArgs, GroupAdmin: true, Handle: h.handlePrices,
+	})
+	r.Handle(Command{
+		Name: CmdChannel, Description: i18n.HelpChannel, Usage: i18n.UsageChannel,
+		Args: channelArgs, Handle: h.handleChannel,
+	})
+	r.Handle(Command{Name: CmdHelp, Description: i18n.HelpHelp, Handle: h.handleHelp})
+
+	r.Handle(Command{Name: CmdStats, Description: i18n.HelpStats, Admin: true, Handle: h.handleStats})
+	r.Handle(Command{
+		Name: CmdBroadcast, Description: i18n.HelpBroadcast, Usage: i18n.AdminBroadcastUsage,
+		Args: Text, Admin: true, Handle: h.handleBroadcast,
+	})
+	r.Handle(Command{Name: CmdFetchNow, Description: i18n.HelpFetchNow, Admin: true, Handle: h.handleFetchNow})
+	r.Handle(Command{
+		Name: CmdUser, Description: i18n.HelpUser, Usage: i18n.AdminUserUsage,
+		Args: chatIDArg, Admin: true, Handle: h.handleUser,
+	})
+
+	return r
+}
+
+// handleUnknown stays quiet in groups unless the command was addressed to
+// this bot, since it is likely meant for another one.
+func (h *BotHandler) handleUnknown(req *Request) {
+	if !req.Message.Chat.IsPrivate() && !strings.Contains(req.Message.CommandWithAt(), "@") {
+		return
+	}
+	h.replyText(req.ChatID, i18n.T(req.Locale, i18n.UnknownCommand))
+}
+
+func (h *BotHandler) handleBadArgs(req *Request) {
+	h.replyText(req.ChatID, i18n.T(req.Locale, req.Command.Usage))
+}
+
+func (h *BotHandler) handleHelp(req *Request) {
+	h.replyText(req.ChatID, h.router.Help(req.Locale, h.isAdmin(senderID(req.Message))))
+}
@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+)
+
+type SettingsConfig struct {
+	NotifyInterval time.Duration
+	DigestHour     int
+	Admins         []int64
+	// RateTemplate overrides the localized rate message when set.
+	RateTemplate  string
+	DefaultLocale string
+	Locales       []string
+	// Currencies are offered by /subscribe after SubscribableCurrencies,
+	// such as the codes of extra providers.
+	Currencies []currency.Code
+}
+
+// Settings holds the parts of the handler that can be swapped at runtime.
+type Settings struct {
+	NotifyInterval time.Duration
+	DigestHour     int
+	DefaultLocale  string
+	Locales        []string
+	// Currencies are the codes /subscribe offers.
+	Currencies   []currency.Code
+	admins       map[int64]bool
+	rateTemplate *template.Template
+}
+
+type rateView struct {
+	*fetcher.Rate
+	Time   time.Time
+	Locale string
+}
+
+// Number formats a value with the separators of the message locale.
+func (v rateView) Number(value decimal.Decimal, decimals int) string {
+	return i18n.FormatNumber(v.Locale, value, decimals)
+}
+
+func NewSettings(cfg SettingsConfig) (*Settings, error) {
+	s := &Settings{
+		NotifyInterval: cfg.NotifyInterval,
+		DigestHour:     cfg.DigestHour,
+		DefaultLocale:  cfg.DefaultLocale,
+		Locales:        cfg.Locales,
+		Currencies:     slices.Clone(SubscribableCurrencies),
+		admins:         make(map[int64]bool, len(cfg.Admins)),
+	}
+	for _, code := range cfg.Currencies {
+		if !slices.Contains(s.Currencies, code) {
+			s.Currencies = append(s.Currencies, code)
+		}
+	}
+	if s.DefaultLocale == "" {
+		s.DefaultLocale = i18n.Default
+	}
+	if len(s.Locales) == 0 {
+		s.Locales = i18n.Supported()
+	}
+	for _, id := range cfg.Admins {
+		s.admins[id] = true
+	}
+
+	if cfg.RateTemplate != "" {
+		tmpl, err := template.New("rate").Option("missingkey=error").Parse(cfg.RateTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("rate template: %w", err)
+		}
+		s.rateTemplate = tmpl
+
+		one := decimal.FromInt(1)
+		sample := &fetcher.Rate{Code: "EUR", Name: "EURO", Unit: 1, Buying: one, Selling: one}
+		if _, err := s.renderRate(sample, time.Now(), s.DefaultLocale); err != nil {
+			return nil, fmt.Errorf("rate template: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+func (s *Settings) IsAdmin(chatID int64) bool {
+	return s.admins[chatID]
+}
+
+// chatLocale maps a stored chat language onto a supported locale, falling
+// back to the default.
+func (s *Settings) chatLocale(language string) string {
+	if locale, ok := i18n.Match(language, s.Locales); ok {
+		return locale
+	}
+	return s.DefaultLocale
+}
+
+func (s *Settings) renderRate(rate *fetcher.Rate, at time.Time, locale string) (string, error) {
+	if s.rateTemplate == nil {
+		return defaultRateMessage(rate, at, locale), nil
+	}
+
+	var b strings.Builder
+	if err := s.rateTemplate.Execute(&b, rateView{Rate: rate, Time: at, Locale: locale}); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// unitLabel names the amount rate's prices are for: the code, preceded by
+// the unit when it is more than one, as in "100 JPY".
+func unitLabel(rate *fetcher.Rate) string {
+	if rate.Unit > 1 {
+		return fmt.Sprintf("%d %s", rate.Unit, rate.Code)
+	}
+	return rate.Code.String()
+}
+
+// defaultRateMessage prices rate for the units the bulletin quotes it in,
+// e.g. "100 JPY→TRY".
+func defaultRateMessage(rate *fetcher.Rate, at time.Time, locale string) string {
+	return i18n.T(locale, i18n.RateMessage, unitLabel(rate),
+		i18n.FormatNumber(locale, rate.Selling, 4),
+		i18n.FormatNumber(locale, rate.Buying, 4),
+		at.Format("15:04"),
+	)
+}
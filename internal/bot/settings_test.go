@@ -0,0 +1,108 @@
+package bot
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+)
+
+func TestNewSettings(t *testing.T) {
+	tests := []struct {
+		name         string
+		template     string
+		locale       string
+		rate         *fetcher.Rate
+		expectedText string
+		expectErrSub string
+	}{
+		{
+			name:         "DefaultMessage",
+			template:     "",
+			locale:       "en",
+			expectedText: "EUR→TRY Selling: 36.1234 Buying: 36.0000 (at 09:30)",
+		},
+		{
+			name:         "DefaultMessageTurkish",
+			template:     "",
+			locale:       "tr",
+			expectedText: "EUR→TRY Satış: 36,1234 Alış: 36,0000 (09:30 itibarıyla)",
+		},
+		{
+			name:         "DefaultMessageUnit",
+			locale:       "en",
+			rate:         &fetcher.Rate{Code: "JPY", Unit: 100, Buying: decimal.MustParse("22.4361"), Selling: decimal.MustParse("22.5848")},
+			expectedText: "100 JPY→TRY Selling: 22.5848 Buying: 22.4361 (at 09:30)",
+		},
+		{
+			name:         "CustomTemplate",
+			template:     `{{.Code}} {{printf "%.2f" .Buying}}/{{printf "%.2f" .Selling}}`,
+			locale:       "en",
+			expectedText: "EUR 36.00/36.12",
+		},
+		{
+			name:         "CustomTemplateLocalizedNumber",
+			template:     `{{.Code}} {{.Number .Selling 2}}`,
+			locale:       "tr",
+			expectedText: "EUR 36,12",
+		},
+		{
+			name:         "SyntaxError",
+			template:     "{{.Buying",
+			expectErrSub: "rate template",
+		},
+		{
+			name:         "UnknownField",
+			template:     "{{.Price}}",
+			expectErrSub: "rate template",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := NewSettings(SettingsConfig{NotifyInterval: time.Hour, Admins: []int64{42}, RateTemplate: tc.template})
+
+			if tc.expectErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErrSub) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectErrSub, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			rate := tc.rate
+			if rate == nil {
+				rate = &fetcher.Rate{Code: "EUR", Unit: 1, Buying: decimal.FromInt(36), Selling: decimal.MustParse("36.1234")}
+			}
+			text, err := s.renderRate(rate, time.Date(2025, 1, 2, 9, 30, 0, 0, time.UTC), tc.locale)
+			if err != nil {
+				t.Fatalf("renderRate: %v", err)
+			}
+			if text != tc.expectedText {
+				t.Errorf("text = %q; want %q", text, tc.expectedText)
+			}
+
+			if !s.IsAdmin(42) || s.IsAdmin(43) {
+				t.Errorf("IsAdmin mismatch for admins [42]")
+			}
+		})
+	}
+}
+
+func TestNewSettingsCurrencies(t *testing.T) {
+	s, err := NewSettings(SettingsConfig{Currencies: []currency.Code{"GAU", "EUR", "XAU"}})
+	if err != nil {
+		t.Fatalf("NewSettings: %v", err)
+	}
+
+	want := append(slices.Clone(SubscribableCurrencies), "GAU", "XAU")
+	if !slices.Equal(s.Currencies, want) {
+		t.Errorf("Currencies = %v; want %v", s.Currencies, want)
+	}
+}
@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"sync"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+)
+
+type trend struct {
+	previous, current decimal.Decimal
+}
+
+// rateTrends remembers the last two distinct forex selling rates of each
+// currency, so the direction of the latest change survives notifications that
+// repeat an unchanged bulletin. Only the raw bulletins are observed; banknote
+// chats show the direction of the forex price.
+type rateTrends struct {
+	mu     sync.Mutex
+	trends map[currency.Code]trend
+}
+
+func newRateTrends() *rateTrends {
+	return &rateTrends{trends: make(map[currency.Code]trend)}
+}
+
+func (t *rateTrends) observe(rates []fetcher.Rate) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, r := range rates {
+		tr, ok := t.trends[r.Code]
+		switch {
+		case !ok:
+			t.trends[r.Code] = trend{previous: r.Selling, current: r.Selling}
+		case !r.Selling.Equal(tr.current):
+			t.trends[r.Code] = trend{previous: tr.current, current: r.Selling}
+		}
+	}
+}
+
+// indicator is ▲ or ▼ for the last change of code, or ▪ when none is known.
+func (t *rateTrends) indicator(code currency.Code) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	tr := t.trends[code]
+	switch tr.current.Cmp(tr.previous) {
+	case 1:
+		return "▲"
+	case -1:
+		return "▼"
+	}
+	return "▪"
+}
@@ -0,0 +1,30 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+)
+
+func TestRateTrends(t *testing.T) {
+	tr := newRateTrends()
+
+	if got := tr.indicator("USD"); got != "▪" {
+		t.Errorf("unknown indicator = %q; want ▪", got)
+	}
+
+	tr.observe([]fetcher.Rate{{Code: "USD", Selling: decimal.MustParse("32.1")}, {Code: "EUR", Selling: decimal.FromInt(35)}})
+	if got := tr.indicator("USD"); got != "▪" {
+		t.Errorf("first observation indicator = %q; want ▪", got)
+	}
+
+	tr.observe([]fetcher.Rate{{Code: "USD", Selling: decimal.MustParse("32.4")}, {Code: "EUR", Selling: decimal.MustParse("34.8")}})
+	tr.observe([]fetcher.Rate{{Code: "USD", Selling: decimal.MustParse("32.4")}, {Code: "EUR", Selling: decimal.MustParse("34.8")}})
+	if got := tr.indicator("USD"); got != "▲" {
+		t.Errorf("USD indicator = %q; want ▲ to survive an unchanged bulletin", got)
+	}
+	if got := tr.indicator("EUR"); got != "▼" {
+		t.Errorf("EUR indicator = %q; want ▼", got)
+	}
+}
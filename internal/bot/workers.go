@@ -0,0 +1,85 @@
+package bot
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"runtime/debug"
+	"sync"
+)
+
+type job struct {
+	chatID int64
+	run    func()
+}
+
+// workerPool runs jobs on a fixed set of workers. Every chat is pinned to
+// one worker, so a chat's updates are handled one at a time and in order.
+type workerPool struct {
+	queues  []chan job
+	wg      sync.WaitGroup
+	logger  *log.Logger
+	onPanic func(chatID int64)
+}
+
+var updatesQueued = new(expvar.Int)
+
+func init() {
+	expvar.Publish("bot_updates_queued", updatesQueued)
+}
+
+func newWorkerPool(workers, queueSize int, logger *log.Logger, onPanic func(chatID int64)) *workerPool {
+	p := &workerPool{
+		queues:  make([]chan job, workers),
+		logger:  logger,
+		onPanic: onPanic,
+	}
+	for i := range p.queues {
+		p.queues[i] = make(chan job, queueSize)
+		p.wg.Add(1)
+		go p.work(p.queues[i])
+	}
+	return p
+}
+
+// submit queues fn for chatID, blocking while that chat's worker is full.
+// It reports false if ctx ends first.
+func (p *workerPool) submit(ctx context.Context, chatID int64, fn func()) bool {
+	queue := p.queues[uint64(chatID)%uint64(len(p.queues))]
+	select {
+	case queue <- job{chatID: chatID, run: fn}:
+		updatesQueued.Add(1)
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// close stops accepting jobs and waits for the queued ones to finish.
+func (p *workerPool) close() {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+	p.wg.Wait()
+}
+
+func (p *workerPool) work(queue chan job) {
+	defer p.wg.Done()
+	for j := range queue {
+		updatesQueued.Add(-1)
+		p.run(j)
+	}
+}
+
+func (p *workerPool) run(j job) {
+	defer func() {
+		if r := recover(); r != nil {
+			handlerPanics.Add(1)
+			p.logger.Printf("PANIC handling update for chat_id=%d: %v\n%s", j.chatID, r, debug.Stack())
+			if p.onPanic != nil && j.chatID != 0 {
+				p.onPanic(j.chatID)
+			}
+		}
+	}()
+	j.run()
+}
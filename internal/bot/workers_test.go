@@ -0,0 +1,90 @@
+package bot
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+)
+
+var discardLogger = log.New(io.Discard, "", 0)
+
+func TestWorkerPool_OrdersPerChat(t *testing.T) {
+	p := newWorkerPool(4, 2, discardLogger, nil)
+
+	var mu sync.Mutex
+	seen := make(map[int64][]int)
+	for i := 0; i < 50; i++ {
+		for _, chatID := range []int64{1, -100123, 7} {
+			i, chatID := i, chatID
+			p.submit(context.Background(), chatID, func() {
+				mu.Lock()
+				defer mu.Unlock()
+				seen[chatID] = append(seen[chatID], i)
+			})
+		}
+	}
+	p.close()
+
+	for chatID, order := range seen {
+		if len(order) != 50 {
+			t.Fatalf("chat %d handled %d jobs; want 50", chatID, len(order))
+		}
+		for i, n := range order {
+			if n != i {
+				t.Fatalf("chat %d handled job %d at position %d", chatID, n, i)
+			}
+		}
+	}
+}
+
+func TestWorkerPool_RecoversPanics(t *testing.T) {
+	var panicked []int64
+	p := newWorkerPool(1, 4, discardLogger, func(chatID int64) { panicked = append(panicked, chatID) })
+
+	ran := false
+	p.submit(context.Background(), 5, func() { panic("boom") })
+	p.submit(context.Background(), 5, func() { ran = true })
+	p.close()
+
+	if len(panicked) != 1 || panicked[0] != 5 {
+		t.Errorf("onPanic calls = %v; want [5]", panicked)
+	}
+	if !ran {
+		t.Errorf("worker stopped after a panic")
+	}
+}
+
+func TestWorkerPool_SubmitRespectsContext(t *testing.T) {
+	p := newWorkerPool(1, 1, discardLogger, nil)
+	release := make(chan struct{})
+	p.submit(context.Background(), 1, func() { <-release })
+	p.submit(context.Background(), 1, func() {})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if p.submit(ctx, 1, func() {}) {
+		t.Errorf("submit to a full queue succeeded")
+	}
+
+	close(release)
+	p.close()
+}
+
+func TestGoBackground_RecoversAndWaits(t *testing.T) {
+	h := &BotHandler{logger: discardLogger}
+
+	finished := false
+	h.goBackground("test", func() { panic("boom") })
+	h.goBackground("test", func() {
+		time.Sleep(10 * time.Millisecond)
+		finished = true
+	})
+	h.background.Wait()
+
+	if !finished {
+		t.Error("Wait returned before the background work finished")
+	}
+}
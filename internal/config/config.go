@@ -1,32 +1,343 @@
 package config
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	TelegramToken        string
-	DatabaseURL          string
-	NotificationInterval int
+	Telegram  TelegramConfig  `yaml:"telegram"`
+	Database  DatabaseConfig  `yaml:"database"`
+	Schedule  ScheduleConfig  `yaml:"schedule"`
+	Fetch     FetchConfig     `yaml:"fetch"`
+	Admins    []int64         `yaml:"admins"`
+	Locale    LocaleConfig    `yaml:"locale"`
+	HTTP      HTTPConfig      `yaml:"http"`
+	Templates TemplatesConfig `yaml:"templates"`
+	Updates   UpdatesConfig   `yaml:"updates"`
+	RateLimit RateLimitConfig `yaml:"rate_limit"`
+}
+
+type TelegramConfig struct {
+	Token string `yaml:"token"`
+}
+
+type DatabaseConfig struct {
+	URL             string        `yaml:"url"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+}
+
+type ScheduleConfig struct {
+	NotifyInterval time.Duration `yaml:"notify_interval"`
+	// DigestHour is the Istanbul hour from which digests are sent.
+	DigestHour int `yaml:"digest_hour"`
+}
+
+type FetchConfig struct {
+	Timeout time.Duration `yaml:"timeout"`
+	// CacheTTL is how long a fetched bulletin is used before the provider is
+	// asked again whether it changed.
+	CacheTTL  time.Duration    `yaml:"cache_ttl"`
+	Retry     RetryConfig      `yaml:"retry"`
+	Breaker   BreakerConfig    `yaml:"breaker"`
+	Providers []ProviderConfig `yaml:"providers"`
+}
+
+// RetryConfig bounds how often a transient provider failure is retried.
+type RetryConfig struct {
+	Attempts  int           `yaml:"attempts"`
+	BaseDelay time.Duration `yaml:"base_delay"`
+	MaxDelay  time.Duration `yaml:"max_delay"`
+}
+
+// BreakerConfig sets when a failing provider's circuit opens and how long it
+// stays open before a probe request is let through.
+type BreakerConfig struct {
+	Failures int           `yaml:"failures"`
+	Cooldown time.Duration `yaml:"cooldown"`
+}
+
+type ProviderConfig struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+	URL  string `yaml:"url"`
+	// Path is the file or directory a file provider reads; with Poll set it
+	// is checked again for a newer file at that interval.
+	Path string        `yaml:"path"`
+	Poll time.Duration `yaml:"poll"`
+	// Mapper names the exchange response shape a crypto provider reads, and
+	// Pairs the coins it prices; BTC, ETH and USDT against TRY when empty.
+	Mapper string       `yaml:"mapper"`
+	Pairs  []PairConfig `yaml:"pairs"`
+}
+
+type PairConfig struct {
+	Code   currency.Code `yaml:"code"`
+	Symbol string        `yaml:"symbol"`
+	// Quote is the currency Symbol is priced in, TRY when empty.
+	Quote currency.Code `yaml:"quote"`
 }
 
-const notificationInterval int = 1
+type LocaleConfig struct {
+	Default   string   `yaml:"default"`
+	Supported []string `yaml:"supported"`
+}
+
+type TemplatesConfig struct {
+	Rate string `yaml:"rate"`
+}
+
+type UpdatesConfig struct {
+	Workers   int `yaml:"workers"`
+	QueueSize int `yaml:"queue_size"`
+}
+
+type RateLimitConfig struct {
+	Backend string        `yaml:"backend"`
+	Burst   int           `yaml:"burst"`
+	Refill  time.Duration `yaml:"refill"`
+}
+
+type HTTPConfig struct {
+	HealthAddr  string `yaml:"health_addr"`
+	MetricsAddr string `yaml:"metrics_addr"`
+}
 
-func Load() (*Config, error) {
-	telegramToken := os.Getenv("TELEGRAM_TOKEN")
-	if telegramToken == "" {
-		return nil, errors.New("TELEGRAM_TOKEN env variable required.")
+const (
+	ProviderTCMB   = "tcmb"
+	ProviderFile   = "file"
+	ProviderMetals = "metals"
+	ProviderCrypto = "crypto"
+
+	RateLimitMemory   = "memory"
+	RateLimitPostgres = "postgres"
+
+	defaultNotifyInterval  = time.Hour
+	defaultDigestHour      = 9
+	defaultFetchTimeout    = 60 * time.Second
+	defaultFetchCacheTTL   = time.Minute
+	defaultRetryAttempts   = 3
+	defaultRetryBaseDelay  = 500 * time.Millisecond
+	defaultRetryMaxDelay   = 10 * time.Second
+	defaultBreakerFailures = 5
+	defaultBreakerCooldown = time.Minute
+	defaultMaxOpenConns    = 10
+	defaultMaxIdleConns    = 5
+	defaultConnMaxLifetime = 30 * time.Minute
+	defaultLocale          = i18n.Default
+	defaultUpdateWorkers   = 8
+	defaultUpdateQueueSize = 100
+	defaultRateLimitBurst  = 5
+	defaultRateLimitRefill = 2 * time.Second
+)
+
+func Default() *Config {
+	return &Config{
+		Database: DatabaseConfig{
+			MaxOpenConns:    defaultMaxOpenConns,
+			MaxIdleConns:    defaultMaxIdleConns,
+			ConnMaxLifetime: defaultConnMaxLifetime,
+		},
+		Schedule: ScheduleConfig{
+			NotifyInterval: defaultNotifyInterval,
+			DigestHour:     defaultDigestHour,
+		},
+		Fetch: FetchConfig{
+			Timeout:  defaultFetchTimeout,
+			CacheTTL: defaultFetchCacheTTL,
+			Retry: RetryConfig{
+				Attempts:  defaultRetryAttempts,
+				BaseDelay: defaultRetryBaseDelay,
+				MaxDelay:  defaultRetryMaxDelay,
+			},
+			Breaker: BreakerConfig{
+				Failures: defaultBreakerFailures,
+				Cooldown: defaultBreakerCooldown,
+			},
+			Providers: []ProviderConfig{
+				{Name: ProviderTCMB, Type: ProviderTCMB, URL: fetcher.TcmbUrl},
+			},
+		},
+		Locale: LocaleConfig{
+			Default:   defaultLocale,
+			Supported: i18n.Supported(),
+		},
+		Updates: UpdatesConfig{
+			Workers:   defaultUpdateWorkers,
+			QueueSize: defaultUpdateQueueSize,
+		},
+		RateLimit: RateLimitConfig{
+			Backend: RateLimitMemory,
+			Burst:   defaultRateLimitBurst,
+			Refill:  defaultRateLimitRefill,
+		},
 	}
+}
 
-	databaseURL := os.Getenv("DATABASE_URL")
-	if databaseURL == "" {
-		return nil, errors.New("DATABASE_URL env variable required.")
+// Load builds the configuration from defaults, the config file, environment
+// variables and command line flags, in increasing order of precedence.
+// Settings only some commands need are left to Validate.
+func Load(flags *Flags) (*Config, error) {
+	cfg := Default()
+
+	path := os.Getenv("CONFIG_FILE")
+	if flags != nil && flags.File != "" {
+		path = flags.File
+	}
+	if path != "" {
+		if err := loadFile(cfg, path); err != nil {
+			return nil, err
+		}
 	}
 
-	return &Config{
-		TelegramToken:        telegramToken,
-		DatabaseURL:          databaseURL,
-		NotificationInterval: notificationInterval,
-	}, nil
+	var problems []string
+	problems = append(problems, applyEnv(cfg)...)
+	if flags != nil {
+		problems = append(problems, flags.apply(cfg)...)
+	}
+	problems = append(problems, validate(cfg)...)
+
+	if len(problems) > 0 {
+		return nil, &ValidationError{Problems: problems}
+	}
+
+	return cfg, nil
+}
+
+func loadFile(cfg *Config, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func applyEnv(cfg *Config) []string {
+	var problems []string
+
+	setSecret := func(name string, dst *string) {
+		value, fromFile, err := secretEnv(name)
+		if err != nil {
+			problems = append(problems, err.Error())
+			return
+		}
+		if value != "" || fromFile {
+			*dst = value
+		}
+	}
+	setString := func(name string, dst *string) {
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			*dst = value
+		}
+	}
+	setInt := func(name string, dst *int) {
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %q is not an integer", name, value))
+				return
+			}
+			*dst = parsed
+		}
+	}
+	setDuration := func(name string, dst *time.Duration) {
+		if value, ok := os.LookupEnv(name); ok && value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %q is not a duration", name, value))
+				return
+			}
+			*dst = parsed
+		}
+	}
+
+	setSecret("TELEGRAM_TOKEN", &cfg.Telegram.Token)
+	setSecret("DATABASE_URL", &cfg.Database.URL)
+	setInt("DB_MAX_OPEN_CONNS", &cfg.Database.MaxOpenConns)
+	setInt("DB_MAX_IDLE_CONNS", &cfg.Database.MaxIdleConns)
+	setDuration("DB_CONN_MAX_LIFETIME", &cfg.Database.ConnMaxLifetime)
+	setDuration("NOTIFY_INTERVAL", &cfg.Schedule.NotifyInterval)
+	setInt("DIGEST_HOUR", &cfg.Schedule.DigestHour)
+	setDuration("FETCH_TIMEOUT", &cfg.Fetch.Timeout)
+	setDuration("FETCH_CACHE_TTL", &cfg.Fetch.CacheTTL)
+	setInt("FETCH_RETRY_ATTEMPTS", &cfg.Fetch.Retry.Attempts)
+	setDuration("FETCH_RETRY_BASE_DELAY", &cfg.Fetch.Retry.BaseDelay)
+	setDuration("FETCH_RETRY_MAX_DELAY", &cfg.Fetch.Retry.MaxDelay)
+	setInt("FETCH_BREAKER_FAILURES", &cfg.Fetch.Breaker.Failures)
+	setDuration("FETCH_BREAKER_COOLDOWN", &cfg.Fetch.Breaker.Cooldown)
+	setInt("UPDATE_WORKERS", &cfg.Updates.Workers)
+	setInt("UPDATE_QUEUE_SIZE", &cfg.Updates.QueueSize)
+	setString("RATE_LIMIT_BACKEND", &cfg.RateLimit.Backend)
+	setInt("RATE_LIMIT_BURST", &cfg.RateLimit.Burst)
+	setDuration("RATE_LIMIT_REFILL", &cfg.RateLimit.Refill)
+	setString("DEFAULT_LOCALE", &cfg.Locale.Default)
+	setString("HEALTH_ADDR", &cfg.HTTP.HealthAddr)
+	setString("METRICS_ADDR", &cfg.HTTP.MetricsAddr)
+
+	if value, ok := os.LookupEnv("ADMIN_IDS"); ok && value != "" {
+		ids, err := parseIDs(value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("ADMIN_IDS: %v", err))
+		} else {
+			cfg.Admins = ids
+		}
+	}
+
+	return problems
+}
+
+// secretEnv reads NAME, or the contents of the file named by NAME_FILE.
+func secretEnv(name string) (string, bool, error) {
+	value := os.Getenv(name)
+	path := os.Getenv(name + "_FILE")
+
+	if path == "" {
+		return value, false, nil
+	}
+	if value != "" {
+		return "", false, fmt.Errorf("%s and %s_FILE are mutually exclusive", name, name)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, fmt.Errorf("%s_FILE: %v", name, err)
+	}
+
+	return strings.TrimSpace(string(content)), true, nil
+}
+
+func parseIDs(value string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a chat id", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
 }
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+type Flags struct {
+	File string
+
+	fs             *flag.FlagSet
+	telegramToken  string
+	databaseURL    string
+	notifyInterval time.Duration
+	fetchTimeout   time.Duration
+	admins         string
+	defaultLocale  string
+	healthAddr     string
+	metricsAddr    string
+}
+
+func BindFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{fs: fs}
+
+	fs.StringVar(&f.File, "config", "", "path to a YAML config file (env CONFIG_FILE)")
+	fs.StringVar(&f.telegramToken, "telegram-token", "", "Telegram bot token (env TELEGRAM_TOKEN)")
+	fs.StringVar(&f.databaseURL, "database-url", "", "Postgres connection URL (env DATABASE_URL)")
+	fs.DurationVar(&f.notifyInterval, "notify-interval", 0, "interval between rate notifications (env NOTIFY_INTERVAL)")
+	fs.DurationVar(&f.fetchTimeout, "fetch-timeout", 0, "HTTP timeout for rate providers (env FETCH_TIMEOUT)")
+	fs.StringVar(&f.admins, "admins", "", "comma separated admin chat ids (env ADMIN_IDS)")
+	fs.StringVar(&f.defaultLocale, "default-locale", "", "default locale for replies (env DEFAULT_LOCALE)")
+	fs.StringVar(&f.healthAddr, "health-addr", "", "listen address for health checks (env HEALTH_ADDR)")
+	fs.StringVar(&f.metricsAddr, "metrics-addr", "", "listen address for metrics (env METRICS_ADDR)")
+
+	return f
+}
+
+func (f *Flags) apply(cfg *Config) []string {
+	var problems []string
+
+	f.fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "telegram-token":
+			cfg.Telegram.Token = f.telegramToken
+		case "database-url":
+			cfg.Database.URL = f.databaseURL
+		case "notify-interval":
+			cfg.Schedule.NotifyInterval = f.notifyInterval
+		case "fetch-timeout":
+			cfg.Fetch.Timeout = f.fetchTimeout
+		case "admins":
+			ids, err := parseIDs(f.admins)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("-admins: %v", err))
+				return
+			}
+			cfg.Admins = ids
+		case "default-locale":
+			cfg.Locale.Default = f.defaultLocale
+		case "health-addr":
+			cfg.HTTP.HealthAddr = f.healthAddr
+		case "metrics-addr":
+			cfg.HTTP.MetricsAddr = f.metricsAddr
+		}
+	})
+
+	return problems
+}
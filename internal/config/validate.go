@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	"github.com/akyTheDev/currency-bot/internal/i18n"
+)
+
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+var providerTypes = []string{ProviderTCMB, ProviderFile, ProviderMetals, ProviderCrypto}
+
+// cryptoQuotes are the currencies crypto pairs may be priced in; other than
+// TRY they must be in the bulletin to be converted.
+var cryptoQuotes = []currency.Code{currency.TRY, currency.EUR}
+
+var rateLimitBackends = []string{RateLimitMemory, RateLimitPostgres}
+
+const minNotifyInterval = time.Minute
+
+// Validate checks the settings only some commands need: the Telegram token
+// when requireBot is set and the database URL when requireDB is.
+func (cfg *Config) Validate(requireBot, requireDB bool) error {
+	var problems []string
+	if requireBot && cfg.Telegram.Token == "" {
+		problems = append(problems, "telegram.token is required (TELEGRAM_TOKEN, TELEGRAM_TOKEN_FILE or -telegram-token)")
+	}
+	if requireDB && cfg.Database.URL == "" {
+		problems = append(problems, "database.url is required (DATABASE_URL, DATABASE_URL_FILE or -database-url)")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func validate(cfg *Config) []string {
+	var problems []string
+	add := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if cfg.Database.MaxOpenConns < 0 {
+		add("database.max_open_conns must not be negative")
+	}
+	if cfg.Database.MaxIdleConns < 0 {
+		add("database.max_idle_conns must not be negative")
+	}
+	if cfg.Database.MaxOpenConns > 0 && cfg.Database.MaxIdleConns > cfg.Database.MaxOpenConns {
+		add("database.max_idle_conns (%d) must not exceed database.max_open_conns (%d)", cfg.Database.MaxIdleConns, cfg.Database.MaxOpenConns)
+	}
+	if cfg.Database.ConnMaxLifetime < 0 {
+		add("database.conn_max_lifetime must not be negative")
+	}
+
+	if cfg.Schedule.NotifyInterval < minNotifyInterval {
+		add("schedule.notify_interval must be at least %s", minNotifyInterval)
+	}
+	if cfg.Schedule.DigestHour < 0 || cfg.Schedule.DigestHour > 23 {
+		add("schedule.digest_hour must be between 0 and 23")
+	}
+
+	if cfg.Fetch.Timeout <= 0 {
+		add("fetch.timeout must be positive")
+	}
+	if cfg.Fetch.CacheTTL < 0 {
+		add("fetch.cache_ttl must not be negative")
+	}
+	if cfg.Fetch.Retry.Attempts < 1 {
+		add("fetch.retry.attempts must be at least 1")
+	}
+	if cfg.Fetch.Retry.BaseDelay <= 0 {
+		add("fetch.retry.base_delay must be positive")
+	}
+	if cfg.Fetch.Retry.MaxDelay < cfg.Fetch.Retry.BaseDelay {
+		add("fetch.retry.max_delay must not be less than fetch.retry.base_delay")
+	}
+	if cfg.Fetch.Breaker.Failures < 1 {
+		add("fetch.breaker.failures must be at least 1")
+	}
+	if cfg.Fetch.Breaker.Cooldown <= 0 {
+		add("fetch.breaker.cooldown must be positive")
+	}
+	if len(cfg.Fetch.Providers) == 0 {
+		add("fetch.providers must list at least one provider")
+	} else if !slices.ContainsFunc(cfg.Fetch.Providers, func(p ProviderConfig) bool { return p.Type == ProviderTCMB || p.Type == ProviderFile }) {
+		// Metals and crypto providers only add to the bulletin of another one.
+		add("fetch.providers must include a %s or %s provider", ProviderTCMB, ProviderFile)
+	}
+	names := map[string]bool{}
+	for i, p := range cfg.Fetch.Providers {
+		if p.Name == "" {
+			add("fetch.providers[%d].name is required", i)
+		} else if names[p.Name] {
+			add("fetch.providers[%d].name %q is duplicated", i, p.Name)
+		}
+		names[p.Name] = true
+
+		if !slices.Contains(providerTypes, p.Type) {
+			add("fetch.providers[%d].type %q is unknown (expected one of %s)", i, p.Type, strings.Join(providerTypes, ", "))
+		}
+		switch p.Type {
+		case ProviderFile:
+			if p.Path == "" {
+				add("fetch.providers[%d].path is required", i)
+			}
+			if p.Poll < 0 {
+				add("fetch.providers[%d].poll must not be negative", i)
+			}
+		default:
+			if p.URL == "" {
+				add("fetch.providers[%d].url is required", i)
+			}
+		}
+		if p.Type == ProviderCrypto {
+			if fetcher.TickerMappers[p.Mapper] == nil {
+				add("fetch.providers[%d].mapper %q is unknown (expected one of %s)", i, p.Mapper, strings.Join(fetcher.MapperNames(), ", "))
+			}
+			for j, pair := range p.Pairs {
+				if pair.Code == "" || pair.Symbol == "" {
+					add("fetch.providers[%d].pairs[%d] needs a code and a symbol", i, j)
+				}
+				if pair.Quote != "" && !slices.Contains(cryptoQuotes, pair.Quote) {
+					add("fetch.providers[%d].pairs[%d].quote %q is unknown (expected one of %s)", i, j, pair.Quote, strings.Join(currency.Strings(cryptoQuotes), ", "))
+				}
+			}
+		}
+	}
+
+	for i, id := range cfg.Admins {
+		if id == 0 {
+			add("admins[%d] must be a non-zero chat id", i)
+		}
+	}
+
+	if len(cfg.Locale.Supported) == 0 {
+		add("locale.supported must list at least one locale")
+	}
+	for _, locale := range cfg.Locale.Supported {
+		if !i18n.IsSupported(locale) {
+			add("locale.supported: %q has no translations (available: %s)", locale, strings.Join(i18n.Supported(), ", "))
+		}
+	}
+	if !slices.Contains(cfg.Locale.Supported, cfg.Locale.Default) {
+		add("locale.default %q is not in locale.supported", cfg.Locale.Default)
+	}
+
+	if cfg.Updates.Workers < 1 {
+		add("updates.workers must be at least 1")
+	}
+	if cfg.Updates.QueueSize < 1 {
+		add("updates.queue_size must be at least 1")
+	}
+
+	if !slices.Contains(rateLimitBackends, cfg.RateLimit.Backend) {
+		add("rate_limit.backend %q is unknown (expected one of %s)", cfg.RateLimit.Backend, strings.Join(rateLimitBackends, ", "))
+	}
+	if cfg.RateLimit.Burst < 1 {
+		add("rate_limit.burst must be at least 1")
+	}
+	if cfg.RateLimit.Refill <= 0 {
+		add("rate_limit.refill must be positive")
+	}
+
+	validateAddr := func(name, addr string) {
+		if addr == "" {
+			return
+		}
+		if _, _, err := net.SplitHostPort(addr); err != nil {
+			add("%s %q is not a valid listen address", name, addr)
+		}
+	}
+	validateAddr("http.health_addr", cfg.HTTP.HealthAddr)
+	validateAddr("http.metrics_addr", cfg.HTTP.MetricsAddr)
+
+	if cfg.Templates.Rate != "" {
+		if _, err := template.New("rate").Parse(cfg.Templates.Rate); err != nil {
+			add("templates.rate: %v", err)
+		}
+	}
+
+	return problems
+}
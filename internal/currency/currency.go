@@ -0,0 +1,241 @@
+// Package currency is a registry of the ISO 4217 currencies the bot quotes,
+// plus the metals and coins its providers add.
+package currency
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Code is an upper case currency code such as "EUR". Parse accepts the codes
+// of the registry and those added with Register, so that coins added through
+// configuration work too.
+type Code string
+
+const (
+	TRY Code = "TRY"
+	USD Code = "USD"
+	EUR Code = "EUR"
+	GBP Code = "GBP"
+	JPY Code = "JPY"
+	XDR Code = "XDR"
+	XAU Code = "XAU"
+	XAG Code = "XAG"
+)
+
+var (
+	ErrInvalid = errors.New("invalid currency code")
+	ErrUnknown = errors.New("unknown currency")
+)
+
+type Currency struct {
+	Code Code
+	// Numeric is the ISO 4217 numeric code, 0 for codes outside ISO 4217.
+	Numeric int
+	// MinorUnits is the number of decimal places ISO 4217 assigns, -1 where
+	// it assigns none (SDR, metals).
+	MinorUnits int
+	Name       string
+	NameTR     string
+	Symbol     string
+	Flag       string
+}
+
+var registry = map[Code]Currency{}
+
+// aliases maps lower case names and symbols to codes, on top of the codes
+// themselves.
+var aliases = map[string]Code{}
+
+// registered holds the codes outside the registry that configured providers
+// quote. It can grow on a configuration reload, hence the lock.
+var (
+	mu         sync.RWMutex
+	registered = map[Code]bool{}
+)
+
+func init() {
+	for _, c := range []struct {
+		Currency
+		aliases []string
+	}{
+		{Currency{TRY, 949, 2, "Turkish Lira", "Türk Lirası", "₺", "🇹🇷"}, []string{"₺", "tl", "lira"}},
+		{Currency{USD, 840, 2, "US Dollar", "ABD Doları", "$", "🇺🇸"}, []string{"$", "dollar", "dolar"}},
+		{Currency{"AUD", 36, 2, "Australian Dollar", "Avustralya Doları", "A$", "🇦🇺"}, nil},
+		{Currency{"DKK", 208, 2, "Danish Krone", "Danimarka Kronu", "kr.", "🇩🇰"}, nil},
+		{Currency{EUR, 978, 2, "Euro", "Euro", "€", "🇪🇺"}, []string{"€", "euro", "avro"}},
+		{Currency{GBP, 826, 2, "Pound Sterling", "İngiliz Sterlini", "£", "🇬🇧"}, []string{"£", "pound", "sterlin", "sterling"}},
+		{Currency{"CHF", 756, 2, "Swiss Franc", "İsviçre Frangı", "CHF", "🇨🇭"}, []string{"franc", "frank"}},
+		{Currency{"SEK", 752, 2, "Swedish Krona", "İsveç Kronu", "kr", "🇸🇪"}, nil},
+		{Currency{"CAD", 124, 2, "Canadian Dollar", "Kanada Doları", "C$", "🇨🇦"}, nil},
+		{Currency{"KWD", 414, 3, "Kuwaiti Dinar", "Kuveyt Dinarı", "KD", "🇰🇼"}, nil},
+		{Currency{"NOK", 578, 2, "Norwegian Krone", "Norveç Kronu", "kr", "🇳🇴"}, nil},
+		{Currency{"SAR", 682, 2, "Saudi Riyal", "Suudi Arabistan Riyali", "SR", "🇸🇦"}, nil},
+		{Currency{JPY, 392, 0, "Japanese Yen", "Japon Yeni", "¥", "🇯🇵"}, []string{"¥", "yen"}},
+		{Currency{"BGN", 975, 2, "Bulgarian Lev", "Bulgar Levası", "лв", "🇧🇬"}, nil},
+		{Currency{"RON", 946, 2, "Romanian Leu", "Rumen Leyi", "lei", "🇷🇴"}, nil},
+		{Currency{"RUB", 643, 2, "Russian Ruble", "Rus Rublesi", "₽", "🇷🇺"}, []string{"₽", "ruble", "rouble"}},
+		{Currency{"IRR", 364, 2, "Iranian Rial", "İran Riyali", "﷼", "🇮🇷"}, nil},
+		{Currency{"CNY", 156, 2, "Chinese Yuan", "Çin Yuanı", "CN¥", "🇨🇳"}, []string{"yuan", "renminbi"}},
+		{Currency{"PKR", 586, 2, "Pakistani Rupee", "Pakistan Rupisi", "₨", "🇵🇰"}, nil},
+		{Currency{"QAR", 634, 2, "Qatari Riyal", "Katar Riyali", "QR", "🇶🇦"}, nil},
+		{Currency{"KRW", 410, 0, "South Korean Won", "Güney Kore Wonu", "₩", "🇰🇷"}, []string{"₩", "won"}},
+		{Currency{"AZN", 944, 2, "Azerbaijani Manat", "Azerbaycan Manatı", "₼", "🇦🇿"}, []string{"₼", "manat"}},
+		{Currency{"AED", 784, 2, "UAE Dirham", "BAE Dirhemi", "AED", "🇦🇪"}, []string{"dirham", "dirhem"}},
+		{Currency{XDR, 960, -1, "Special Drawing Right", "Özel Çekme Hakkı", "SDR", ""}, []string{"sdr"}},
+		{Currency{XAU, 959, -1, "Gold (troy ounce)", "Altın (ons)", "XAU", "🥇"}, []string{"gold", "ons"}},
+		{Currency{XAG, 961, -1, "Silver (troy ounce)", "Gümüş (ons)", "XAG", "🥈"}, []string{"silver"}},
+		{Currency{"GAU", 0, 2, "Gold (gram)", "Gram altın", "gr", "🥇"}, []string{"altın", "altin"}},
+		{Currency{"GAG", 0, 2, "Silver (gram)", "Gram gümüş", "gr", "🥈"}, []string{"gümüş", "gumus"}},
+		{Currency{"BTC", 0, 8, "Bitcoin", "Bitcoin", "₿", ""}, []string{"₿", "bitcoin"}},
+		{Currency{"ETH", 0, 18, "Ether", "Ether", "Ξ", ""}, []string{"Ξ", "ether", "ethereum"}},
+		{Currency{"USDT", 0, 6, "Tether", "Tether", "₮", ""}, []string{"₮", "tether"}},
+	} {
+		registry[c.Code] = c.Currency
+		for _, alias := range c.aliases {
+			aliases[normalize(alias)] = c.Code
+		}
+	}
+}
+
+// Register adds codes outside the registry, such as coins of a configured
+// crypto provider, to the ones Parse accepts.
+func Register(codes ...Code) {
+	mu.Lock()
+	defer mu.Unlock()
+	for _, code := range codes {
+		if _, ok := registry[code]; !ok && code.wellFormed() {
+			registered[code] = true
+		}
+	}
+}
+
+// Parse reads a code, case-insensitively, or one of its names or symbols,
+// such as "euro", "€" or "avro". Well-formed codes that are neither in the
+// registry nor registered fail with ErrUnknown.
+func Parse(s string) (Code, error) {
+	code, err := parse(s)
+	if err != nil {
+		return "", err
+	}
+	if !code.Known() {
+		return "", fmt.Errorf("%w: %q", ErrUnknown, s)
+	}
+	return code, nil
+}
+
+// parse is Parse without the registry check.
+func parse(s string) (Code, error) {
+	s = strings.TrimSpace(s)
+	if code, ok := aliases[normalize(s)]; ok {
+		return code, nil
+	}
+
+	code := Code(strings.ToUpper(s))
+	if !code.wellFormed() {
+		return "", fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	return code, nil
+}
+
+// ParseAll parses each of fields, failing on the first invalid one.
+func ParseAll(fields []string) ([]Code, error) {
+	codes := make([]Code, 0, len(fields))
+	for _, field := range fields {
+		code, err := Parse(field)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// Lookup returns the registry entry of code.
+func Lookup(code Code) (Currency, bool) {
+	c, ok := registry[code]
+	return c, ok
+}
+
+// Known reports whether code is in the registry or registered.
+func (c Code) Known() bool {
+	if _, ok := registry[c]; ok {
+		return true
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	return registered[c]
+}
+
+func (c Code) String() string {
+	return string(c)
+}
+
+// Strings converts codes for APIs that take plain strings.
+func Strings(codes []Code) []string {
+	out := make([]string, len(codes))
+	for i, code := range codes {
+		out[i] = string(code)
+	}
+	return out
+}
+
+// wellFormed reports whether c is three to five upper case ASCII letters.
+func (c Code) wellFormed() bool {
+	if len(c) < 3 || len(c) > 5 {
+		return false
+	}
+	for _, r := range c {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// UnmarshalText parses text as Parse does, so configuration and JSON can use
+// aliases too, but accepts any well-formed code: configuration is where new
+// codes come from before they are registered.
+func (c *Code) UnmarshalText(text []byte) error {
+	code, err := parse(string(text))
+	if err != nil {
+		return err
+	}
+	*c = code
+	return nil
+}
+
+func (c Code) Value() (driver.Value, error) {
+	return string(c), nil
+}
+
+// Scan reads a stored code, rejecting malformed ones. Codes no longer
+// registered are accepted, so rows left from an earlier configuration don't
+// break the queries reading them.
+func (c *Code) Scan(src any) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("currency: cannot scan %T", src)
+	}
+
+	code := Code(s)
+	if !code.wellFormed() {
+		return fmt.Errorf("%w: %q", ErrInvalid, s)
+	}
+	*c = code
+	return nil
+}
+
+// normalize lower cases s; "İ" becomes a plain "i" rather than "i" with a
+// combining dot.
+func normalize(s string) string {
+	return strings.ReplaceAll(strings.ToLower(s), "̇", "")
+}
@@ -0,0 +1,120 @@
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Code
+		wantErr error
+	}{
+		{input: "EUR", want: EUR},
+		{input: " usd ", want: USD},
+		{input: "euro", want: EUR},
+		{input: "Avro", want: EUR},
+		{input: "€", want: EUR},
+		{input: "£", want: GBP},
+		{input: "STERLİN", want: GBP},
+		{input: "ALTIN", want: "GAU"},
+		{input: "usdt", want: "USDT"},
+		{input: "doge", wantErr: ErrUnknown},
+		{input: "hello", wantErr: ErrUnknown},
+		{input: "", wantErr: ErrInvalid},
+		{input: "eu", wantErr: ErrInvalid},
+		{input: "EUROPE", wantErr: ErrInvalid},
+		{input: "US1", wantErr: ErrInvalid},
+		{input: "ÇİN", wantErr: ErrInvalid},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := Parse(tc.input)
+			if tc.wantErr != nil {
+				if !errors.Is(err, tc.wantErr) {
+					t.Errorf("Parse(%q) = %q, %v; want %v", tc.input, got, err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tc.want {
+				t.Errorf("Parse(%q) = %q, %v; want %q", tc.input, got, err, tc.want)
+			}
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("PEPE", EUR, "x")
+	if got, err := Parse("pepe"); err != nil || got != "PEPE" || !Code("PEPE").Known() {
+		t.Errorf("Parse(pepe) after Register = %q, %v", got, err)
+	}
+	if _, ok := Lookup("PEPE"); ok {
+		t.Error("Lookup(PEPE) found a registry entry")
+	}
+	if Code("x").Known() {
+		t.Error("Register accepted a malformed code")
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	codes, err := ParseAll([]string{"usd", "€", "jpy"})
+	if err != nil || len(codes) != 3 || codes[0] != USD || codes[1] != EUR || codes[2] != JPY {
+		t.Errorf("ParseAll = %v, %v", codes, err)
+	}
+
+	if _, err := ParseAll([]string{"usd", "x"}); !errors.Is(err, ErrInvalid) {
+		t.Errorf("ParseAll with an invalid field error = %v; want %v", err, ErrInvalid)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	jpy, ok := Lookup(JPY)
+	if !ok || jpy.Numeric != 392 || jpy.MinorUnits != 0 || jpy.NameTR != "Japon Yeni" || jpy.Flag != "🇯🇵" {
+		t.Errorf("Lookup(JPY) = %+v, %v", jpy, ok)
+	}
+
+	if Code("DOGE").Known() || !XDR.Known() {
+		t.Errorf("Known reports DOGE or misses XDR")
+	}
+}
+
+func TestScan(t *testing.T) {
+	var c Code
+	if err := c.Scan([]byte("EUR")); err != nil || c != EUR {
+		t.Errorf("Scan([]byte) = %q, %v", c, err)
+	}
+	if err := c.Scan("usd"); !errors.Is(err, ErrInvalid) || c != EUR {
+		t.Errorf("Scan(lower case) = %q, %v; want %v and no change", c, err, ErrInvalid)
+	}
+	// A coin dropped from the configuration still reads back.
+	if err := c.Scan("ABCDE"); err != nil || c != "ABCDE" || c.Known() {
+		t.Errorf("Scan(unregistered) = %q, %v", c, err)
+	}
+	if err := c.Scan(42); err == nil {
+		t.Errorf("Scan(int) error = nil")
+	}
+
+	if v, err := GBP.Value(); err != nil || v != "GBP" {
+		t.Errorf("Value = %v, %v", v, err)
+	}
+}
+
+func TestUnmarshalText(t *testing.T) {
+	var pair struct {
+		Code  Code `json:"code"`
+		Quote Code `json:"quote"`
+	}
+	if err := json.Unmarshal([]byte(`{"code": "btc", "quote": "€"}`), &pair); err != nil || pair.Code != "BTC" || pair.Quote != EUR {
+		t.Errorf("Unmarshal = %+v, %v", pair, err)
+	}
+	// Configuration declares new coins, so unregistered codes are accepted.
+	if err := json.Unmarshal([]byte(`{"code": "doge"}`), &pair); err != nil || pair.Code != "DOGE" {
+		t.Errorf("Unmarshal of an unregistered code = %+v, %v", pair, err)
+	}
+	if err := json.Unmarshal([]byte(`{"code": "b"}`), &pair); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Unmarshal of an invalid code error = %v; want %v", err, ErrInvalid)
+	}
+}
@@ -0,0 +1,369 @@
+// Package decimal implements the fixed-point numbers used for exchange
+// rates and amounts, so that parsing, storage, conversion and formatting
+// never go through binary floating point.
+package decimal
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of fractional digits a Decimal keeps.
+const Scale = 6
+
+const factor = 1_000_000
+
+var (
+	ErrSyntax         = errors.New("decimal: invalid syntax")
+	ErrOverflow       = errors.New("decimal: value out of range")
+	ErrDivisionByZero = errors.New("decimal: division by zero")
+)
+
+// Decimal is a signed number with Scale fractional digits, stored as an
+// integer count of 10^-Scale. The zero value is 0.
+type Decimal struct {
+	units int64
+}
+
+// RoundingMode decides which way results with more than the wanted number
+// of fractional digits go.
+type RoundingMode int
+
+const (
+	// RoundHalfEven rounds to the nearest value, ties to the even neighbour.
+	RoundHalfEven RoundingMode = iota
+	// RoundHalfUp rounds to the nearest value, ties away from zero.
+	RoundHalfUp
+	// RoundDown truncates towards zero.
+	RoundDown
+	// RoundUp rounds away from zero.
+	RoundUp
+)
+
+var (
+	bigFactor = big.NewInt(factor)
+	minUnits  = big.NewInt(-1 << 63)
+	maxUnits  = big.NewInt(1<<63 - 1)
+)
+
+func FromInt(n int64) Decimal {
+	return Decimal{units: n * factor}
+}
+
+// Parse reads a plain decimal such as "35.1234" or "-0.5". Digits beyond
+// Scale are rounded half to even.
+func Parse(s string) (Decimal, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Decimal{}, ErrSyntax
+	}
+
+	neg := false
+	switch s[0] {
+	case '-':
+		neg = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	intPart, fracPart, hasPoint := strings.Cut(s, ".")
+	if (intPart == "" && fracPart == "") || (hasPoint && fracPart == "" && intPart == "") {
+		return Decimal{}, ErrSyntax
+	}
+	for _, part := range []string{intPart, fracPart} {
+		for _, r := range part {
+			if r < '0' || r > '9' {
+				return Decimal{}, ErrSyntax
+			}
+		}
+	}
+
+	n, ok := new(big.Int).SetString(intPart+fracPart+"0", 10)
+	if !ok {
+		return Decimal{}, ErrSyntax
+	}
+	if neg {
+		n.Neg(n)
+	}
+	// n is the value scaled by 10^(len(fracPart)+1).
+	d := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(len(fracPart)+1)), nil)
+	return fromBig(divRound(n.Mul(n, bigFactor), d, RoundHalfEven))
+}
+
+// MustParse is Parse for constants; it panics on invalid input.
+func MustParse(s string) Decimal {
+	d, err := Parse(s)
+	if err != nil {
+		panic(fmt.Sprintf("decimal.MustParse(%q): %v", s, err))
+	}
+	return d
+}
+
+// FromFloat converts f using its shortest decimal representation.
+func FromFloat(f float64) (Decimal, error) {
+	return Parse(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+func (d Decimal) Add(e Decimal) Decimal { return Decimal{units: d.units + e.units} }
+func (d Decimal) Sub(e Decimal) Decimal { return Decimal{units: d.units - e.units} }
+func (d Decimal) Neg() Decimal          { return Decimal{units: -d.units} }
+
+func (d Decimal) Abs() Decimal {
+	if d.units < 0 {
+		return d.Neg()
+	}
+	return d
+}
+
+// Cmp returns -1, 0 or +1 as d is less than, equal to or greater than e.
+func (d Decimal) Cmp(e Decimal) int {
+	switch {
+	case d.units < e.units:
+		return -1
+	case d.units > e.units:
+		return 1
+	}
+	return 0
+}
+
+func (d Decimal) Equal(e Decimal) bool { return d.units == e.units }
+func (d Decimal) IsZero() bool         { return d.units == 0 }
+
+// Sign returns -1, 0 or +1.
+func (d Decimal) Sign() int { return d.Cmp(Decimal{}) }
+
+// MulInt multiplies exactly by n.
+func (d Decimal) MulInt(n int64) (Decimal, error) {
+	return fromBig(new(big.Int).Mul(big.NewInt(d.units), big.NewInt(n)))
+}
+
+// Mul multiplies d by e, rounding the product to Scale digits.
+func (d Decimal) Mul(e Decimal, mode RoundingMode) (Decimal, error) {
+	return MulDiv(d, e, FromInt(1), mode)
+}
+
+// Div divides d by e, rounding the quotient to Scale digits.
+func (d Decimal) Div(e Decimal, mode RoundingMode) (Decimal, error) {
+	return MulDiv(d, FromInt(1), e, mode)
+}
+
+// MulDiv computes a*b/c with a single rounding at the end.
+func MulDiv(a, b, c Decimal, mode RoundingMode) (Decimal, error) {
+	if c.IsZero() {
+		return Decimal{}, ErrDivisionByZero
+	}
+	n := new(big.Int).Mul(big.NewInt(a.units), big.NewInt(b.units))
+	return fromBig(divRound(n, big.NewInt(c.units), mode))
+}
+
+// Round rounds d to places fractional digits, 0 <= places <= Scale.
+func (d Decimal) Round(places int, mode RoundingMode) Decimal {
+	if places >= Scale {
+		return d
+	}
+	step := big.NewInt(pow10(Scale - max(places, 0)))
+	q := divRound(big.NewInt(d.units), step, mode)
+	return Decimal{units: q.Mul(q, step).Int64()}
+}
+
+func (d Decimal) Float64() float64 {
+	return float64(d.units) / factor
+}
+
+// StringFixed formats d with exactly places fractional digits, rounding
+// half up.
+func (d Decimal) StringFixed(places int) string {
+	places = min(max(places, 0), Scale)
+	r := d.Round(places, RoundHalfUp)
+
+	sign := ""
+	units := r.units
+	if units < 0 {
+		sign = "-"
+	}
+	digits := strconv.FormatUint(absUnits(units), 10)
+	if len(digits) <= Scale {
+		digits = strings.Repeat("0", Scale-len(digits)+1) + digits
+	}
+	intPart, fracPart := digits[:len(digits)-Scale], digits[len(digits)-Scale:]
+	if places == 0 {
+		return sign + intPart
+	}
+	return sign + intPart + "." + fracPart[:places]
+}
+
+// String formats d without trailing fractional zeros.
+func (d Decimal) String() string {
+	s := d.StringFixed(Scale)
+	if strings.Contains(s, ".") {
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	}
+	return s
+}
+
+// Format lets %f, %.2f and friends print d exactly, so rate templates
+// written for floats keep working; %e and %g go through float64.
+func (d Decimal) Format(f fmt.State, verb rune) {
+	var s string
+	switch verb {
+	case 'f', 'F':
+		places, ok := f.Precision()
+		if !ok {
+			places = Scale
+		}
+		s = d.StringFixed(places)
+		if places > Scale {
+			s += strings.Repeat("0", places-Scale)
+		}
+	case 'v', 's':
+		s = d.String()
+	case 'q':
+		s = strconv.Quote(d.String())
+	case 'e', 'E', 'g', 'G':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), d.Float64())
+		return
+	default:
+		fmt.Fprintf(f, "%%!%c(decimal.Decimal=%s)", verb, d.String())
+		return
+	}
+
+	if f.Flag('+') && d.Sign() >= 0 {
+		s = "+" + s
+	}
+	if width, ok := f.Width(); ok && len(s) < width {
+		pad := strings.Repeat(" ", width-len(s))
+		if f.Flag('-') {
+			s += pad
+		} else {
+			s = pad + s
+		}
+	}
+	fmt.Fprint(f, s)
+}
+
+// Places is the number of fractional digits String prints.
+func (d Decimal) Places() int {
+	_, frac, _ := strings.Cut(d.String(), ".")
+	return len(frac)
+}
+
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses text; empty text is zero, as encoding/xml treats
+// empty numeric elements.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	if len(strings.TrimSpace(string(text))) == 0 {
+		*d = Decimal{}
+		return nil
+	}
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// MarshalJSON writes d as a JSON number.
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a string holding one.
+func (d *Decimal) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(s); err == nil {
+		s = unquoted
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value stores d as text, which Postgres reads into NUMERIC exactly.
+func (d Decimal) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+func (d *Decimal) Scan(src any) error {
+	var err error
+	switch v := src.(type) {
+	case nil:
+		*d = Decimal{}
+	case string:
+		*d, err = Parse(v)
+	case []byte:
+		*d, err = Parse(string(v))
+	case int64:
+		*d = FromInt(v)
+	case float64:
+		*d, err = FromFloat(v)
+	default:
+		err = fmt.Errorf("decimal: cannot scan %T", src)
+	}
+	return err
+}
+
+func fromBig(n *big.Int) (Decimal, error) {
+	if n.Cmp(minUnits) < 0 || n.Cmp(maxUnits) > 0 {
+		return Decimal{}, ErrOverflow
+	}
+	return Decimal{units: n.Int64()}, nil
+}
+
+// divRound divides n by d, rounding as mode says.
+func divRound(n, d *big.Int, mode RoundingMode) *big.Int {
+	q, r := new(big.Int).QuoRem(n, d, new(big.Int))
+	if r.Sign() == 0 {
+		return q
+	}
+
+	away := int64(n.Sign() * d.Sign())
+	twice := new(big.Int).Abs(r)
+	twice.Lsh(twice, 1)
+	half := twice.Cmp(new(big.Int).Abs(d))
+
+	var up bool
+	switch mode {
+	case RoundDown:
+		up = false
+	case RoundUp:
+		up = true
+	case RoundHalfUp:
+		up = half >= 0
+	default:
+		up = half > 0 || (half == 0 && q.Bit(0) == 1)
+	}
+	if up {
+		q.Add(q, big.NewInt(away))
+	}
+	return q
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for range n {
+		p *= 10
+	}
+	return p
+}
+
+func absUnits(units int64) uint64 {
+	if units < 0 {
+		return uint64(-(units + 1)) + 1
+	}
+	return uint64(units)
+}
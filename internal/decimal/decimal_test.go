@@ -0,0 +1,159 @@
+package decimal
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in, want string
+		wantErr  bool
+	}{
+		{in: "35.1234", want: "35.1234"},
+		{in: " -0.5 ", want: "-0.5"},
+		{in: "+12", want: "12"},
+		{in: ".25", want: "0.25"},
+		{in: "7.", want: "7"},
+		{in: "20.0000", want: "20"},
+		{in: "0.0000005", want: "0"},
+		{in: "0.0000015", want: "0.000002"},
+		{in: "0.12345650", want: "0.123456"},
+		{in: "", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "1e5", wantErr: true},
+		{in: "1,5", wantErr: true},
+		{in: "--1", wantErr: true},
+		{in: "99999999999999999999", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		got, err := Parse(tc.in)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %v; want error", tc.in, got)
+			}
+			continue
+		}
+		if err != nil || got.String() != tc.want {
+			t.Errorf("Parse(%q) = %v, %v; want %s", tc.in, got, err, tc.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := MustParse("0.1"), MustParse("0.2")
+	if got := a.Add(b); !got.Equal(MustParse("0.3")) {
+		t.Errorf("0.1 + 0.2 = %v", got)
+	}
+	if got := a.Sub(b); got.String() != "-0.1" || got.Sign() != -1 || got.Abs().String() != "0.1" {
+		t.Errorf("0.1 - 0.2 = %v", got)
+	}
+	if a.Cmp(b) != -1 || b.Cmp(a) != 1 || a.Cmp(a) != 0 {
+		t.Error("Cmp returned an unexpected order")
+	}
+
+	if got, err := MustParse("36.1234").Mul(MustParse("100"), RoundHalfEven); err != nil || got.String() != "3612.34" {
+		t.Errorf("Mul = %v, %v", got, err)
+	}
+	if got, err := FromInt(2).Div(FromInt(3), RoundHalfEven); err != nil || got.String() != "0.666667" {
+		t.Errorf("Div = %v, %v", got, err)
+	}
+	if _, err := FromInt(1).Div(Decimal{}, RoundHalfEven); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("Div by zero error = %v", err)
+	}
+	if _, err := MustParse("9000000000000").Mul(MustParse("9000000000000"), RoundHalfEven); !errors.Is(err, ErrOverflow) {
+		t.Errorf("Mul overflow error = %v", err)
+	}
+	if got, err := MulDiv(FromInt(1000), MustParse("22.5"), FromInt(100), RoundHalfEven); err != nil || got.String() != "225" {
+		t.Errorf("MulDiv = %v, %v", got, err)
+	}
+	if got, err := FromInt(3).MulInt(100); err != nil || !got.Equal(FromInt(300)) {
+		t.Errorf("MulInt = %v, %v", got, err)
+	}
+}
+
+func TestRound(t *testing.T) {
+	tests := []struct {
+		in   string
+		mode RoundingMode
+		want string
+	}{
+		{"2.345", RoundHalfEven, "2.34"},
+		{"2.355", RoundHalfEven, "2.36"},
+		{"2.345", RoundHalfUp, "2.35"},
+		{"-2.345", RoundHalfUp, "-2.35"},
+		{"2.349", RoundDown, "2.34"},
+		{"-2.349", RoundDown, "-2.34"},
+		{"2.341", RoundUp, "2.35"},
+		{"-2.341", RoundUp, "-2.35"},
+		{"2.34", RoundUp, "2.34"},
+	}
+
+	for _, tc := range tests {
+		if got := MustParse(tc.in).Round(2, tc.mode); got.String() != tc.want {
+			t.Errorf("Round(%s, 2, %d) = %v; want %s", tc.in, tc.mode, got, tc.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	d := MustParse("36.12345")
+	tests := map[string]string{
+		"%v":    "36.12345",
+		"%s":    "36.12345",
+		"%.2f":  "36.12",
+		"%.4f":  "36.1235",
+		"%.8f":  "36.12345000",
+		"%f":    "36.123450",
+		"%8.1f": "    36.1",
+		"%+.0f": "+36",
+		"%g":    "36.12345",
+	}
+	for format, want := range tests {
+		if got := fmt.Sprintf(format, d); got != want {
+			t.Errorf("Sprintf(%q) = %q; want %q", format, got, want)
+		}
+	}
+	if got := MustParse("-0.5").StringFixed(0); got != "-1" {
+		t.Errorf("StringFixed(-0.5, 0) = %q; want -1", got)
+	}
+	if got := MustParse("-0.004").StringFixed(2); got != "0.00" {
+		t.Errorf("StringFixed(-0.004, 2) = %q; want 0.00", got)
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	var rate struct {
+		Buying  Decimal `xml:"ForexBuying"`
+		Selling Decimal `xml:"ForexSelling"`
+	}
+	if err := xml.Unmarshal([]byte("<r><ForexBuying>36.1234</ForexBuying><ForexSelling></ForexSelling></r>"), &rate); err != nil {
+		t.Fatalf("xml: %v", err)
+	}
+	if rate.Buying.String() != "36.1234" || !rate.Selling.IsZero() {
+		t.Errorf("xml = %+v", rate)
+	}
+
+	out, err := json.Marshal(map[string]Decimal{"selling": MustParse("36.1")})
+	if err != nil || string(out) != `{"selling":36.1}` {
+		t.Errorf("json = %s, %v", out, err)
+	}
+	var in struct{ A, B Decimal }
+	if err := json.Unmarshal([]byte(`{"A": 1.25, "B": "2.5"}`), &in); err != nil || in.A.String() != "1.25" || in.B.String() != "2.5" {
+		t.Errorf("json in = %+v, %v", in, err)
+	}
+
+	var scanned Decimal
+	for _, src := range []any{"35.100000", []byte("35.1"), 35.1} {
+		if err := scanned.Scan(src); err != nil || scanned.String() != "35.1" {
+			t.Errorf("Scan(%v) = %v, %v", src, scanned, err)
+		}
+	}
+	if v, err := MustParse("35.1").Value(); err != nil || v != "35.1" {
+		t.Errorf("Value = %v, %v", v, err)
+	}
+}
@@ -5,5 +5,8 @@ import "errors"
 var (
 	ErrUserAlreadyExists = errors.New("user already exists")
 	ErrUserNotFound      = errors.New("user not found")
+	ErrAlreadySubscribed = errors.New("already subscribed")
+	ErrNotSubscribed     = errors.New("not subscribed")
+	ErrCurrencyNotFound  = errors.New("currency not found")
 	ErrGeneric           = errors.New("server error")
 )
@@ -0,0 +1,70 @@
+package fetcher
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC)
+	b := NewBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	fail := func() {
+		t.Helper()
+		if err := b.Allow(); err != nil {
+			t.Fatalf("Allow = %v; want nil", err)
+		}
+		b.Record(false)
+	}
+
+	fail()
+	if b.State() != BreakerClosed {
+		t.Fatalf("state after one failure = %s; want closed", b.State())
+	}
+	fail()
+	if b.State() != BreakerOpen || !errors.Is(b.Allow(), ErrCircuitOpen) {
+		t.Fatalf("state after two failures = %s; want open and rejecting", b.State())
+	}
+
+	now = now.Add(time.Minute)
+	if b.State() != BreakerHalfOpen {
+		t.Fatalf("state after cooldown = %s; want half-open", b.State())
+	}
+	fail()
+	if b.State() != BreakerOpen {
+		t.Fatalf("state after a failed probe = %s; want open", b.State())
+	}
+
+	now = now.Add(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("probe Allow = %v", err)
+	}
+	if !errors.Is(b.Allow(), ErrCircuitOpen) {
+		t.Error("second call allowed while the probe is in flight")
+	}
+	b.Record(true)
+	if b.State() != BreakerClosed {
+		t.Errorf("state after a successful probe = %s; want closed", b.State())
+	}
+	fail()
+	if b.State() != BreakerClosed {
+		t.Errorf("failure count not reset by success; state = %s", b.State())
+	}
+}
+
+func TestChainHealth(t *testing.T) {
+	open := NewBreaker(1, time.Hour)
+	open.Allow()
+	open.Record(false)
+
+	health := NewSwitch(NewChain(
+		Provider{Name: "tcmb", Fetcher: &fakeFetcher{}, Breaker: open},
+		Provider{Name: "file", Fetcher: &fakeFetcher{}},
+	)).Health()
+
+	if len(health) != 2 || health[0] != (ProviderHealth{"tcmb", "open"}) || health[1] != (ProviderHealth{"file", "closed"}) {
+		t.Errorf("Health = %+v", health)
+	}
+}
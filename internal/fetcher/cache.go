@@ -0,0 +1,74 @@
+package fetcher
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// rateCache keeps the rates of a provider without validators for a TTL, and
+// serves them marked stale when refreshing fails.
+type rateCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.Mutex
+	rates     []Rate
+	fetchedAt time.Time
+}
+
+func newRateCache(ttl time.Duration) *rateCache {
+	return &rateCache{ttl: ttl, now: time.Now}
+}
+
+// get returns a copy of the cached rates, refreshing them with fetch once
+// the TTL has passed.
+func (c *rateCache) get(fetch func() ([]Rate, error)) ([]Rate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.rates != nil && c.now().Sub(c.fetchedAt) < c.ttl {
+		return slices.Clone(c.rates), nil
+	}
+
+	rates, err := fetch()
+	if err != nil {
+		if c.rates == nil {
+			return nil, err
+		}
+		stale := slices.Clone(c.rates)
+		for i := range stale {
+			stale[i].Stale = true
+		}
+		return stale, nil
+	}
+
+	c.rates, c.fetchedAt = rates, c.now()
+	return slices.Clone(rates), nil
+}
+
+// get downloads url, failing on any status but 200 and on bodies larger
+// than maxBulletinSize.
+func get(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("http GET: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBulletinSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	if len(body) > maxBulletinSize {
+		return nil, fmt.Errorf("response exceeds %d bytes", maxBulletinSize)
+	}
+	return body, nil
+}
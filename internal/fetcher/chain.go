@@ -0,0 +1,143 @@
+package fetcher
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+)
+
+type BulletinFetcher interface {
+	FetchBulletin() ([]Rate, error)
+}
+
+type Provider struct {
+	Name    string
+	Fetcher RateFetcher
+	// Breaker guards the provider's requests; nil when it has none.
+	Breaker *Breaker
+	// Extra providers add their rates to the bulletin, such as metals, rather
+	// than standing in for it when the providers before them fail.
+	Extra bool
+}
+
+// ProviderHealth is the circuit state of one provider.
+type ProviderHealth struct {
+	Name    string `json:"name"`
+	Circuit string `json:"circuit"`
+}
+
+// Chain tries its providers in order and returns the first successful result,
+// with the rates of its extra providers appended to bulletins.
+type Chain struct {
+	providers []Provider
+}
+
+func NewChain(providers ...Provider) *Chain {
+	return &Chain{providers: providers}
+}
+
+func (c *Chain) FetchRate() (*Rate, error) {
+	var errs []error
+	for _, p := range c.providers {
+		if p.Extra {
+			continue
+		}
+		rate, err := p.Fetcher.FetchRate()
+		if err == nil {
+			return rate, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+	}
+
+	return nil, fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+}
+
+func (c *Chain) FetchBulletin() ([]Rate, error) {
+	var errs []error
+	for _, p := range c.providers {
+		bf, ok := p.Fetcher.(BulletinFetcher)
+		if !ok || p.Extra {
+			continue
+		}
+		rates, err := bf.FetchBulletin()
+		if err == nil {
+			return c.withExtras(rates), nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+	}
+
+	if len(errs) == 0 {
+		return nil, errors.New("no provider supports bulletins")
+	}
+	return nil, fmt.Errorf("all providers failed: %w", errors.Join(errs...))
+}
+
+// withExtras appends the rates of the extra providers whose codes the
+// bulletin lacks, converted to TRY. A failing extra provider only leaves its
+// rates out, as does a quote currency the bulletin doesn't list.
+func (c *Chain) withExtras(rates []Rate) []Rate {
+	bulletin := rates
+	for _, p := range c.providers {
+		bf, ok := p.Fetcher.(BulletinFetcher)
+		if !ok || !p.Extra {
+			continue
+		}
+		extra, err := bf.FetchBulletin()
+		if err != nil {
+			continue
+		}
+		for _, r := range extra {
+			if slices.ContainsFunc(rates, func(have Rate) bool { return have.Code == r.Code }) {
+				continue
+			}
+			if r, err = inTRY(r, bulletin); err == nil {
+				rates = append(rates, r)
+			}
+		}
+	}
+	return rates
+}
+
+// inTRY converts a rate quoted in another bulletin currency to TRY, buying
+// at that currency's buying price and selling at its selling price.
+func inTRY(r Rate, bulletin []Rate) (Rate, error) {
+	if r.Quote == "" {
+		return r, nil
+	}
+
+	i := slices.IndexFunc(bulletin, func(q Rate) bool { return q.Code == r.Quote })
+	if i < 0 {
+		return Rate{}, fmt.Errorf("%s: no %s rate to convert with", r.Code, r.Quote)
+	}
+	quote := bulletin[i]
+	unit := decimal.FromInt(int64(max(quote.Unit, 1)))
+
+	buying, err := decimal.MulDiv(r.Buying, quote.Buying, unit, decimal.RoundHalfEven)
+	if err != nil {
+		return Rate{}, err
+	}
+	selling, err := decimal.MulDiv(r.Selling, quote.Selling, unit, decimal.RoundHalfEven)
+	if err != nil {
+		return Rate{}, err
+	}
+
+	r.Buying, r.Selling = buying, selling
+	r.ForexBuying, r.ForexSelling = buying, selling
+	r.Stale = r.Stale || quote.Stale
+	r.Quote = ""
+	return r, nil
+}
+
+func (c *Chain) Health() []ProviderHealth {
+	health := make([]ProviderHealth, 0, len(c.providers))
+	for _, p := range c.providers {
+		state := BreakerClosed
+		if p.Breaker != nil {
+			state = p.Breaker.State()
+		}
+		health = append(health, ProviderHealth{Name: p.Name, Circuit: state.String()})
+	}
+	return health
+}
@@ -0,0 +1,130 @@
+package fetcher
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+)
+
+type fakeFetcher struct {
+	rate  *Rate
+	rates []Rate
+	err   error
+	calls int
+}
+
+func (f *fakeFetcher) FetchRate() (*Rate, error) {
+	f.calls++
+	return f.rate, f.err
+}
+
+func (f *fakeFetcher) FetchBulletin() ([]Rate, error) {
+	f.calls++
+	return f.rates, f.err
+}
+
+type rateOnlyFetcher struct{}
+
+func (rateOnlyFetcher) FetchRate() (*Rate, error) { return &Rate{}, nil }
+
+func TestChainFetchRate(t *testing.T) {
+	t.Run("FirstSucceeds", func(t *testing.T) {
+		first := &fakeFetcher{rate: &Rate{Buying: decimal.FromInt(1)}}
+		second := &fakeFetcher{rate: &Rate{Buying: decimal.FromInt(2)}}
+
+		rate, err := NewChain(Provider{Name: "first", Fetcher: first}, Provider{Name: "second", Fetcher: second}).FetchRate()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !rate.Buying.Equal(decimal.FromInt(1)) {
+			t.Errorf("rate = %+v; want the first provider's rate", rate)
+		}
+		if second.calls != 0 {
+			t.Errorf("second provider called %d times; want 0", second.calls)
+		}
+	})
+
+	t.Run("FallsBack", func(t *testing.T) {
+		first := &fakeFetcher{err: errors.New("down")}
+		second := &fakeFetcher{rate: &Rate{Buying: decimal.FromInt(2)}}
+
+		rate, err := NewChain(Provider{Name: "first", Fetcher: first}, Provider{Name: "second", Fetcher: second}).FetchRate()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !rate.Buying.Equal(decimal.FromInt(2)) {
+			t.Errorf("rate = %+v; want the second provider's rate", rate)
+		}
+	})
+
+	t.Run("AllFail", func(t *testing.T) {
+		first := &fakeFetcher{err: errors.New("down")}
+		second := &fakeFetcher{err: errors.New("timeout")}
+
+		_, err := NewChain(Provider{Name: "first", Fetcher: first}, Provider{Name: "second", Fetcher: second}).FetchRate()
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		for _, sub := range []string{"all providers failed", "first: down", "second: timeout"} {
+			if !strings.Contains(err.Error(), sub) {
+				t.Errorf("error %q does not contain %q", err.Error(), sub)
+			}
+		}
+	})
+}
+
+func TestChainFetchBulletin(t *testing.T) {
+	t.Run("SkipsProvidersWithoutBulletins", func(t *testing.T) {
+		bulletin := &fakeFetcher{rates: []Rate{{Code: "USD"}}}
+
+		rates, err := NewChain(Provider{Name: "rate-only", Fetcher: rateOnlyFetcher{}}, Provider{Name: "bulletin", Fetcher: bulletin}).FetchBulletin()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(rates) != 1 || rates[0].Code != "USD" {
+			t.Errorf("rates = %+v; want the bulletin provider's rates", rates)
+		}
+	})
+
+	t.Run("NoBulletinProvider", func(t *testing.T) {
+		_, err := NewChain(Provider{Name: "rate-only", Fetcher: rateOnlyFetcher{}}).FetchBulletin()
+		if err == nil || !strings.Contains(err.Error(), "no provider supports bulletins") {
+			t.Errorf("expected unsupported error, got %v", err)
+		}
+	})
+}
+
+func TestChainFetchBulletinExtras(t *testing.T) {
+	base := &fakeFetcher{rates: []Rate{{Code: "USD"}, {Code: "XAU", Source: SourceTCMB}}}
+	metals := &fakeFetcher{rates: []Rate{{Code: "GAU"}, {Code: "XAU", Source: SourceMetals}}}
+	down := &fakeFetcher{err: errors.New("down")}
+
+	chain := NewChain(
+		Provider{Name: "metals", Fetcher: metals, Extra: true},
+		Provider{Name: "tcmb", Fetcher: base},
+		Provider{Name: "crypto", Fetcher: down, Extra: true},
+	)
+
+	rates, err := chain.FetchBulletin()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var codes []string
+	for _, r := range rates {
+		codes = append(codes, r.Code.String()+"/"+r.Source)
+	}
+	if got := strings.Join(codes, " "); got != "USD/ XAU/tcmb GAU/" {
+		t.Errorf("bulletin = %s; want the base rates followed by new extra codes", got)
+	}
+
+	if _, err := chain.FetchRate(); err != nil || metals.calls != 1 {
+		t.Errorf("FetchRate = %v with %d metals calls; want extras skipped", err, metals.calls)
+	}
+
+	base.err = errors.New("timeout")
+	if _, err := chain.FetchBulletin(); err == nil || strings.Contains(err.Error(), "metals") {
+		t.Errorf("error = %v; want only the base provider's failure", err)
+	}
+}
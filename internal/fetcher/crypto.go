@@ -0,0 +1,197 @@
+package fetcher
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+)
+
+const SourceCrypto = "crypto"
+
+// Ticker is the best bid and ask of one exchange symbol.
+type Ticker struct {
+	Bid decimal.Decimal
+	Ask decimal.Decimal
+}
+
+// TickerMapper reads the tickers, by exchange symbol, out of an exchange's
+// ticker response.
+type TickerMapper func(body []byte) (map[string]Ticker, error)
+
+// TickerMappers are the exchange response shapes a CryptoFetcher can read,
+// by name; register a mapper here to support another exchange.
+var TickerMappers = map[string]TickerMapper{
+	"binance": mapBinance,
+	"btcturk": mapBTCTurk,
+}
+
+// CryptoPair maps a coin code to the exchange symbol that prices it, and the
+// currency that symbol quotes in.
+type CryptoPair struct {
+	Code   currency.Code
+	Symbol string
+	Quote  currency.Code
+}
+
+// DefaultCryptoPairs are BTC, ETH and USDT against TRY.
+var DefaultCryptoPairs = []CryptoPair{
+	{Code: "BTC", Symbol: "BTCTRY", Quote: currency.TRY},
+	{Code: "ETH", Symbol: "ETHTRY", Quote: currency.TRY},
+	{Code: "USDT", Symbol: "USDTTRY", Quote: currency.TRY},
+}
+
+var coinNames = map[currency.Code]string{
+	"BTC":  "BITCOIN",
+	"ETH":  "ETHEREUM",
+	"USDT": "TETHER",
+}
+
+// CryptoFetcher reads coin prices from an exchange's REST ticker endpoint.
+// Buying is the bid and Selling the ask. Prices quoted in a currency other
+// than TRY carry it in Rate.Quote, and the chain converts them with the
+// bulletin. Prices are cached like a MetalsFetcher's.
+type CryptoFetcher struct {
+	client *http.Client
+	url    string
+	mapper TickerMapper
+	pairs  []CryptoPair
+	cache  *rateCache
+}
+
+// NewCryptoFetcher prices DefaultCryptoPairs when pairs is empty, and sends
+// requests through transport, or the default transport when nil.
+func NewCryptoFetcher(url string, mapper TickerMapper, pairs []CryptoPair, timeoutSeconds int, cacheTTL time.Duration, transport http.RoundTripper) *CryptoFetcher {
+	if len(pairs) == 0 {
+		pairs = DefaultCryptoPairs
+	}
+	return &CryptoFetcher{
+		url:    url,
+		mapper: mapper,
+		pairs:  pairs,
+		client: &http.Client{
+			Timeout:   time.Duration(timeoutSeconds) * time.Second,
+			Transport: transport,
+		},
+		cache: newRateCache(cacheTTL),
+	}
+}
+
+// FetchRate returns the first configured pair.
+func (c *CryptoFetcher) FetchRate() (*Rate, error) {
+	rates, err := c.FetchBulletin()
+	if err != nil {
+		return &Rate{}, err
+	}
+	return &rates[0], nil
+}
+
+func (c *CryptoFetcher) FetchBulletin() ([]Rate, error) {
+	return c.cache.get(func() ([]Rate, error) {
+		body, err := get(c.client, c.url)
+		if err != nil {
+			return nil, err
+		}
+		tickers, err := c.mapper(body)
+		if err != nil {
+			return nil, err
+		}
+		return c.rates(tickers)
+	})
+}
+
+// rates fails when a configured symbol is missing, since a pair the exchange
+// doesn't list is a configuration mistake.
+func (c *CryptoFetcher) rates(tickers map[string]Ticker) ([]Rate, error) {
+	var missing []string
+	rates := make([]Rate, 0, len(c.pairs))
+	for _, pair := range c.pairs {
+		ticker, ok := tickers[strings.ToUpper(pair.Symbol)]
+		if !ok || ticker.Bid.Sign() <= 0 || ticker.Ask.Sign() <= 0 {
+			missing = append(missing, pair.Symbol)
+			continue
+		}
+
+		rate := Rate{
+			Code:         pair.Code,
+			Name:         cmp.Or(coinNames[pair.Code], pair.Code.String()),
+			Unit:         1,
+			Buying:       ticker.Bid,
+			Selling:      ticker.Ask,
+			ForexBuying:  ticker.Bid,
+			ForexSelling: ticker.Ask,
+			Source:       SourceCrypto,
+		}
+		if pair.Quote != "" && pair.Quote != currency.TRY {
+			rate.Quote = pair.Quote
+		}
+		rates = append(rates, rate)
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("no ticker for %s", strings.Join(missing, ", "))
+	}
+	return rates, nil
+}
+
+// mapBinance reads /api/v3/ticker/bookTicker, one object or an array of
+// them.
+func mapBinance(body []byte) (map[string]Ticker, error) {
+	type bookTicker struct {
+		Symbol   string          `json:"symbol"`
+		BidPrice decimal.Decimal `json:"bidPrice"`
+		AskPrice decimal.Decimal `json:"askPrice"`
+	}
+
+	var tickers []bookTicker
+	if err := json.Unmarshal(body, &tickers); err != nil {
+		var one bookTicker
+		if json.Unmarshal(body, &one) != nil {
+			return nil, fmt.Errorf("parse JSON: %w", err)
+		}
+		tickers = []bookTicker{one}
+	}
+
+	out := make(map[string]Ticker, len(tickers))
+	for _, t := range tickers {
+		out[strings.ToUpper(t.Symbol)] = Ticker{Bid: t.BidPrice, Ask: t.AskPrice}
+	}
+	return out, nil
+}
+
+// mapBTCTurk reads /api/v2/ticker.
+func mapBTCTurk(body []byte) (map[string]Ticker, error) {
+	var resp struct {
+		Success bool `json:"success"`
+		Data    []struct {
+			Pair string          `json:"pair"`
+			Bid  decimal.Decimal `json:"bid"`
+			Ask  decimal.Decimal `json:"ask"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("parse JSON: %w", err)
+	}
+	if !resp.Success {
+		return nil, errors.New("exchange reported failure")
+	}
+
+	out := make(map[string]Ticker, len(resp.Data))
+	for _, t := range resp.Data {
+		out[strings.ToUpper(t.Pair)] = Ticker{Bid: t.Bid, Ask: t.Ask}
+	}
+	return out, nil
+}
+
+// MapperNames lists the registered ticker mappers, sorted.
+func MapperNames() []string {
+	return slices.Sorted(maps.Keys(TickerMappers))
+}
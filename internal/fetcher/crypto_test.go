@@ -0,0 +1,122 @@
+package fetcher
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+)
+
+// tickerServer serves a fixture from testdata/crypto.
+func tickerServer(t *testing.T, file string) *httptest.Server {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "crypto", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(body)
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func TestCryptoFetcher(t *testing.T) {
+	tests := []struct {
+		name, mapper, file string
+		pairs              []CryptoPair
+		want               string
+		expectErrSub       string
+	}{
+		{
+			name: "Binance", mapper: "binance", file: "binance_bookTicker.json",
+			want: "BTC 3461520/3462847 ETH 127361/127420 USDT 35.33/35.34",
+		},
+		{
+			name: "BTCTurk", mapper: "btcturk", file: "btcturk_ticker.json",
+			want: "BTC 3461250/3462999 ETH 127355/127431 USDT 35.336/35.339",
+		},
+		{
+			name: "QuotedInEUR", mapper: "binance", file: "binance_bookTicker.json",
+			pairs: []CryptoPair{{Code: "BTC", Symbol: "BTCEUR", Quote: "EUR"}},
+			want:  "BTC 94672.51/94681.09 EUR",
+		},
+		{
+			name: "MissingSymbol", mapper: "btcturk", file: "btcturk_ticker.json",
+			pairs:        []CryptoPair{{Code: "BTC", Symbol: "BTCTRY"}, {Code: "SOL", Symbol: "SOLTRY"}},
+			expectErrSub: "no ticker for SOLTRY",
+		},
+		{
+			name: "WrongMapper", mapper: "btcturk", file: "binance_bookTicker.json",
+			expectErrSub: "parse JSON",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := tickerServer(t, tc.file)
+			rates, err := NewCryptoFetcher(ts.URL, TickerMappers[tc.mapper], tc.pairs, 2, 0, nil).FetchBulletin()
+
+			if tc.expectErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErrSub) {
+					t.Errorf("error = %v; want it to contain %q", err, tc.expectErrSub)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var got []string
+			for _, r := range rates {
+				got = append(got, strings.TrimSpace(r.Code.String()+" "+r.Buying.String()+"/"+r.Selling.String()+" "+r.Quote.String()))
+				if r.Source != SourceCrypto || r.Unit != 1 {
+					t.Errorf("%s = %+v", r.Code, r)
+				}
+			}
+			if strings.Join(got, " ") != tc.want {
+				t.Errorf("rates = %s; want %s", strings.Join(got, " "), tc.want)
+			}
+		})
+	}
+}
+
+func TestMapBinanceSingleTicker(t *testing.T) {
+	tickers, err := mapBinance([]byte(`{"symbol":"btctry","bidPrice":"1.5","askPrice":"2"}`))
+	if err != nil || tickers["BTCTRY"].Ask.String() != "2" {
+		t.Errorf("mapBinance = %v, %v", tickers, err)
+	}
+}
+
+func TestChainConvertsCryptoQuotes(t *testing.T) {
+	ts := tickerServer(t, "binance_bookTicker.json")
+	pairs := []CryptoPair{{Code: "BTC", Symbol: "BTCTRY"}, {Code: "ETH", Symbol: "ETHEUR", Quote: "EUR"}}
+	bulletin := &fakeFetcher{rates: []Rate{{Code: "EUR", Unit: 1, Buying: decimal.MustParse("36.4667"), Selling: decimal.MustParse("36.5323")}}}
+
+	rates, err := NewChain(
+		Provider{Name: "tcmb", Fetcher: bulletin},
+		Provider{Name: "crypto", Fetcher: NewCryptoFetcher(ts.URL, mapBinance, pairs, 2, 0, nil), Extra: true},
+	).FetchBulletin()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// 3483.12 × 36.4667 and 3484.01 × 36.5323.
+	if len(rates) != 3 || rates[1].Code != "BTC" || rates[2].Code != "ETH" ||
+		rates[2].Buying.String() != "127017.892104" || rates[2].Selling.String() != "127278.898523" || rates[2].Quote != "" {
+		t.Errorf("rates = %+v", rates)
+	}
+
+	bulletin.rates = []Rate{{Code: "USD", Unit: 1}}
+	if rates, _ := NewChain(
+		Provider{Name: "tcmb", Fetcher: bulletin},
+		Provider{Name: "crypto", Fetcher: NewCryptoFetcher(ts.URL, mapBinance, pairs, 2, 0, nil), Extra: true},
+	).FetchBulletin(); len(rates) != 2 || rates[1].Code != "BTC" {
+		t.Errorf("rates without EUR = %+v; want ETH left out", rates)
+	}
+}
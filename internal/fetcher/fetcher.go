@@ -1,8 +1,71 @@
 package fetcher
 
+import (
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+)
+
+// Price types a chat can choose between. Forex prices apply to transfers and
+// are what Buying and Selling hold by default; banknote prices apply to cash.
+const (
+	PriceForex    = "forex"
+	PriceBanknote = "banknote"
+)
+
 type Rate struct {
-	Selling float64
-	Buying  float64
+	Code currency.Code `json:"code"`
+	// Name is the English name; NameTR the Turkish one when the source has it.
+	Name    string          `json:"name"`
+	NameTR  string          `json:"name_tr,omitempty"`
+	Unit    int             `json:"unit"`
+	Selling decimal.Decimal `json:"selling"`
+	Buying  decimal.Decimal `json:"buying"`
+
+	ForexSelling    decimal.Decimal `json:"forex_selling"`
+	ForexBuying     decimal.Decimal `json:"forex_buying"`
+	BanknoteSelling decimal.Decimal `json:"banknote_selling"`
+	BanknoteBuying  decimal.Decimal `json:"banknote_buying"`
+	// CrossRateUSD is units of the currency per USD, CrossRateOther USD per
+	// unit of it; TCMB fills one of them for most currencies.
+	CrossRateUSD   decimal.Decimal `json:"cross_rate_usd"`
+	CrossRateOther decimal.Decimal `json:"cross_rate_other"`
+
+	// Source names the provider; Date is the day the rate is effective for
+	// and BulletinNo the number of the bulletin it was published in.
+	Source     string    `json:"source,omitempty"`
+	Date       time.Time `json:"date"`
+	BulletinNo string    `json:"bulletin_no,omitempty"`
+	// Stale is set when the provider could not be reached and served the
+	// last rate it had instead.
+	Stale bool `json:"stale,omitempty"`
+	// Quote is the currency the prices are in when it isn't TRY; the chain
+	// converts such rates of extra providers to TRY.
+	Quote currency.Code `json:"quote,omitempty"`
+}
+
+// WithPrices returns r with Buying and Selling taken from the given price
+// type. Rates without prices of that type, such as currencies TCMB quotes no
+// banknote prices for, are returned unchanged.
+func (r Rate) WithPrices(priceType string) Rate {
+	buying, selling := r.ForexBuying, r.ForexSelling
+	if priceType == PriceBanknote {
+		buying, selling = r.BanknoteBuying, r.BanknoteSelling
+	}
+	if !buying.IsZero() && !selling.IsZero() {
+		r.Buying, r.Selling = buying, selling
+	}
+	return r
+}
+
+// WithPrices applies Rate.WithPrices to every rate, in a new slice.
+func WithPrices(rates []Rate, priceType string) []Rate {
+	out := make([]Rate, len(rates))
+	for i, r := range rates {
+		out[i] = r.WithPrices(priceType)
+	}
+	return out
 }
 
 type RateFetcher interface {
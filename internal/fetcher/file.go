@@ -0,0 +1,228 @@
+package fetcher
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+)
+
+const SourceFile = "file"
+
+// fileFormats maps the supported extensions to their parsers: a TCMB
+// bulletin, the output of `fetch -json`, or a CSV with a header row.
+var fileFormats = map[string]func([]byte) ([]Rate, error){
+	".xml":  parseBulletin,
+	".json": parseJSONRates,
+	".csv":  parseCSVRates,
+}
+
+// FileFetcher reads rates from a file on disk, or from the last file in name
+// order in a directory, so bulletins named by date are picked up in turn.
+// With a poll interval the path is checked again once that interval has
+// passed, and a changed file is read; without one it is read once. When a
+// reread fails the previous rates are served, marked stale.
+type FileFetcher struct {
+	path string
+	poll time.Duration
+	now  func() time.Time
+
+	mu        sync.Mutex
+	loaded    *loadedFile
+	checkedAt time.Time
+}
+
+type loadedFile struct {
+	path    string
+	modTime time.Time
+	rates   []Rate
+}
+
+func NewFileFetcher(path string, poll time.Duration) *FileFetcher {
+	return &FileFetcher{path: path, poll: poll, now: time.Now}
+}
+
+func (f *FileFetcher) FetchRate() (*Rate, error) {
+	rates, err := f.FetchBulletin()
+	if err != nil {
+		return &Rate{}, err
+	}
+
+	for _, rate := range rates {
+		if rate.Code == currency.EUR {
+			return &rate, nil
+		}
+	}
+
+	return &Rate{}, errors.New("EUR not found")
+}
+
+func (f *FileFetcher) FetchBulletin() ([]Rate, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.loaded != nil && (f.poll <= 0 || f.now().Sub(f.checkedAt) < f.poll) {
+		return slices.Clone(f.loaded.rates), nil
+	}
+
+	loaded, err := f.load()
+	if err != nil {
+		if f.loaded == nil {
+			return nil, err
+		}
+		rates := slices.Clone(f.loaded.rates)
+		for i := range rates {
+			rates[i].Stale = true
+		}
+		return rates, nil
+	}
+
+	f.loaded, f.checkedAt = loaded, f.now()
+	return slices.Clone(loaded.rates), nil
+}
+
+// load reads the current file unless it is the one already loaded.
+func (f *FileFetcher) load() (*loadedFile, error) {
+	path, info, err := f.current()
+	if err != nil {
+		return nil, err
+	}
+	if f.loaded != nil && f.loaded.path == path && f.loaded.modTime.Equal(info.ModTime()) {
+		return f.loaded, nil
+	}
+
+	parse := fileFormats[strings.ToLower(filepath.Ext(path))]
+	if parse == nil {
+		return nil, fmt.Errorf("%s: unsupported file type (expected .xml, .json or .csv)", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	body, err := io.ReadAll(io.LimitReader(file, maxBulletinSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", path, err)
+	}
+	if len(body) > maxBulletinSize {
+		return nil, fmt.Errorf("%s exceeds %d bytes", path, maxBulletinSize)
+	}
+
+	rates, err := parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &loadedFile{path: path, modTime: info.ModTime(), rates: rates}, nil
+}
+
+// current resolves the configured path to the file to read.
+func (f *FileFetcher) current() (string, os.FileInfo, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", nil, err
+	}
+	if !info.IsDir() {
+		return f.path, info, nil
+	}
+
+	entries, err := os.ReadDir(f.path)
+	if err != nil {
+		return "", nil, err
+	}
+	// ReadDir sorts by name, so the last supported file is the newest.
+	for _, entry := range slices.Backward(entries) {
+		if entry.IsDir() || fileFormats[strings.ToLower(filepath.Ext(entry.Name()))] == nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return "", nil, err
+		}
+		return filepath.Join(f.path, entry.Name()), info, nil
+	}
+
+	return "", nil, fmt.Errorf("%s: no .xml, .json or .csv files", f.path)
+}
+
+func parseJSONRates(body []byte) ([]Rate, error) {
+	var rates []Rate
+	if err := json.Unmarshal(body, &rates); err != nil {
+		return nil, fmt.Errorf("parse JSON: %w", err)
+	}
+	for i := range rates {
+		if rates[i].Source == "" {
+			rates[i].Source = SourceFile
+		}
+		rates[i].Stale = false
+	}
+	return rates, nil
+}
+
+// parseCSVRates reads a header row naming at least code, buying and selling,
+// optionally followed by unit, name and date (2006-01-02) columns in any
+// order.
+func parseCSVRates(body []byte) ([]Rate, error) {
+	records, err := csv.NewReader(strings.NewReader(string(body))).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("parse CSV: missing header row")
+	}
+
+	columns := map[string]int{}
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"code", "buying", "selling"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("parse CSV: missing %q column", required)
+		}
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	rates := make([]Rate, 0, len(records)-1)
+	for line, record := range records[1:] {
+		rate := Rate{Name: field(record, "name"), Unit: 1, Source: SourceFile}
+		if rate.Code, err = currency.Parse(field(record, "code")); err != nil {
+			return nil, fmt.Errorf("parse CSV: line %d: %w", line+2, err)
+		}
+		if unit := field(record, "unit"); unit != "" {
+			if rate.Unit, err = strconv.Atoi(unit); err != nil || rate.Unit < 1 {
+				return nil, fmt.Errorf("parse CSV: line %d: invalid unit %q", line+2, unit)
+			}
+		}
+		if rate.Buying, err = decimal.Parse(field(record, "buying")); err != nil {
+			return nil, fmt.Errorf("parse CSV: line %d: buying: %w", line+2, err)
+		}
+		if rate.Selling, err = decimal.Parse(field(record, "selling")); err != nil {
+			return nil, fmt.Errorf("parse CSV: line %d: selling: %w", line+2, err)
+		}
+		if date := field(record, "date"); date != "" {
+			if rate.Date, err = time.Parse(time.DateOnly, date); err != nil {
+				return nil, fmt.Errorf("parse CSV: line %d: invalid date %q", line+2, date)
+			}
+		}
+		rate.ForexBuying, rate.ForexSelling = rate.Buying, rate.Selling
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
@@ -0,0 +1,108 @@
+package fetcher
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeRates(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileFetcherFormats(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name, content string
+		wantSource    string
+	}{
+		{
+			name:       "bulletin.xml",
+			content:    `<Tarih_Date Date="01/02/2025"><Currency Kod="EUR"><Unit>1</Unit><CurrencyName>EURO</CurrencyName><ForexBuying>36.4667</ForexBuying><ForexSelling>36.5323</ForexSelling></Currency></Tarih_Date>`,
+			wantSource: SourceTCMB,
+		},
+		{
+			name:       "rates.json",
+			content:    `[{"code":"EUR","name":"EURO","unit":1,"buying":"36.4667","selling":36.5323,"date":"2025-01-02T00:00:00Z","stale":true}]`,
+			wantSource: SourceFile,
+		},
+		{
+			name:       "rates.csv",
+			content:    "Code,Unit,Buying,Selling,Date\nUSD,1,35.2763,35.3398,2025-01-02\nEUR,1,36.4667,36.5323,2025-01-02\n",
+			wantSource: SourceFile,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rate, err := NewFileFetcher(writeRates(t, dir, tc.name, tc.content), 0).FetchRate()
+			if err != nil {
+				t.Fatalf("FetchRate: %v", err)
+			}
+			if rate.Code != "EUR" || rate.Buying.String() != "36.4667" || rate.Selling.String() != "36.5323" ||
+				!rate.Date.Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)) || rate.Source != tc.wantSource || rate.Stale {
+				t.Errorf("rate = %+v", rate)
+			}
+		})
+	}
+
+	for name, content := range map[string]string{
+		"bad.csv":   "code,buying\nEUR,1\n",
+		"bad.json":  `{"code":"EUR"}`,
+		"rates.txt": "EUR 1",
+	} {
+		if _, err := NewFileFetcher(writeRates(t, dir, name, content), 0).FetchBulletin(); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
+
+func TestFileFetcherPollsDirectory(t *testing.T) {
+	dir := t.TempDir()
+	writeRates(t, dir, "2025-01-02.csv", "code,buying,selling\nEUR,36.4667,36.5323\n")
+	writeRates(t, dir, "notes.txt", "ignored")
+
+	now := time.Date(2025, 1, 3, 9, 0, 0, 0, time.UTC)
+	f := NewFileFetcher(dir, time.Minute)
+	f.now = func() time.Time { return now }
+
+	fetch := func() *Rate {
+		t.Helper()
+		rate, err := f.FetchRate()
+		if err != nil {
+			t.Fatalf("FetchRate: %v", err)
+		}
+		return rate
+	}
+
+	if rate := fetch(); rate.Selling.String() != "36.5323" {
+		t.Fatalf("first rate = %+v", rate)
+	}
+
+	writeRates(t, dir, "2025-01-03.csv", "code,buying,selling\nEUR,36.5,36.6\n")
+	if rate := fetch(); rate.Selling.String() != "36.5323" {
+		t.Errorf("rate within the poll interval = %v; want the first file's", rate.Selling)
+	}
+
+	now = now.Add(time.Minute)
+	if rate := fetch(); rate.Selling.String() != "36.6" {
+		t.Errorf("rate after polling = %v; want the newest file's", rate.Selling)
+	}
+
+	writeRates(t, dir, "2025-01-04.csv", "code,buying\nEUR,37\n")
+	now = now.Add(time.Minute)
+	if rate := fetch(); !rate.Stale || rate.Selling.String() != "36.6" {
+		t.Errorf("rate after a bad file = %+v; want the last good one, stale", rate)
+	}
+
+	if _, err := NewFileFetcher(t.TempDir(), 0).FetchBulletin(); err == nil || !strings.Contains(err.Error(), "no .xml") {
+		t.Errorf("empty directory error = %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package fetcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+)
+
+const SourceMetals = "metals"
+
+// Pseudo-currency codes for precious metals: the ISO 4217 codes quote a troy
+// ounce, the G codes a gram, as Turkish markets usually do.
+const (
+	CodeGoldOunce   currency.Code = currency.XAU
+	CodeGoldGram    currency.Code = "GAU"
+	CodeSilverOunce currency.Code = currency.XAG
+	CodeSilverGram  currency.Code = "GAG"
+)
+
+// MetalCodes are the codes a MetalsFetcher provides.
+var MetalCodes = []currency.Code{CodeGoldGram, CodeGoldOunce, CodeSilverGram, CodeSilverOunce}
+
+// A troy ounce is 31.1034768 g. Decimal keeps six places, so the ratio is
+// applied as an exact fraction.
+var (
+	gramsPerOunceNum = decimal.FromInt(311034768)
+	gramsPerOunceDen = decimal.FromInt(10000000)
+)
+
+type metal struct {
+	ounceCode, gramCode currency.Code
+	name, nameTR        string
+}
+
+var metals = map[string]metal{
+	"XAU": {ounceCode: CodeGoldOunce, gramCode: CodeGoldGram, name: "GOLD", nameTR: "ALTIN"},
+	"XAG": {ounceCode: CodeSilverOunce, gramCode: CodeSilverGram, name: "SILVER", nameTR: "GÜMÜŞ"},
+}
+
+// metalsFeed is the feed a MetalsFetcher reads, as JSON:
+//
+//	{"date": "2025-01-02", "metals": [{"code": "XAU", "unit": "ounce", "buying": "92650.12", "selling": "92790.40"}]}
+//
+// or as XML:
+//
+//	<Metals Date="2025-01-02"><Metal Code="XAU" Unit="gram"><Buying>2978.81</Buying><Selling>2983.32</Selling></Metal></Metals>
+//
+// Prices are in TRY per troy ounce ("ounce", "oz") or per gram ("gram", "g").
+type metalsFeed struct {
+	XMLName xml.Name     `json:"-" xml:"Metals"`
+	Date    string       `json:"date" xml:"Date,attr"`
+	Metals  []metalPrice `json:"metals" xml:"Metal"`
+}
+
+type metalPrice struct {
+	Code    string          `json:"code" xml:"Code,attr"`
+	Unit    string          `json:"unit" xml:"Unit,attr"`
+	Buying  decimal.Decimal `json:"buying" xml:"Buying"`
+	Selling decimal.Decimal `json:"selling" xml:"Selling"`
+}
+
+// MetalsFetcher reads gold and silver prices from a JSON or XML feed and
+// provides each metal per troy ounce and per gram. Prices are reused for the
+// cache TTL, and the last ones are served, marked stale, when the feed can't
+// be reached.
+type MetalsFetcher struct {
+	client *http.Client
+	url    string
+	cache  *rateCache
+}
+
+// NewMetalsFetcher sends requests through transport, or the default
+// transport when nil.
+func NewMetalsFetcher(url string, timeoutSeconds int, cacheTTL time.Duration, transport http.RoundTripper) *MetalsFetcher {
+	return &MetalsFetcher{
+		url: url,
+		client: &http.Client{
+			Timeout:   time.Duration(timeoutSeconds) * time.Second,
+			Transport: transport,
+		},
+		cache: newRateCache(cacheTTL),
+	}
+}
+
+// FetchRate returns gram gold, the price most users follow.
+func (m *MetalsFetcher) FetchRate() (*Rate, error) {
+	rates, err := m.FetchBulletin()
+	if err != nil {
+		return &Rate{}, err
+	}
+
+	for _, rate := range rates {
+		if rate.Code == CodeGoldGram {
+			return &rate, nil
+		}
+	}
+
+	return &Rate{}, errors.New("gold not found")
+}
+
+func (m *MetalsFetcher) FetchBulletin() ([]Rate, error) {
+	return m.cache.get(func() ([]Rate, error) {
+		body, err := get(m.client, m.url)
+		if err != nil {
+			return nil, err
+		}
+		return parseMetals(body)
+	})
+}
+
+func parseMetals(body []byte) ([]Rate, error) {
+	var feed metalsFeed
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '<' {
+		if err := xml.Unmarshal(trimmed, &feed); err != nil {
+			return nil, fmt.Errorf("parse XML: %w", err)
+		}
+	} else if err := json.Unmarshal(trimmed, &feed); err != nil {
+		return nil, fmt.Errorf("parse JSON: %w", err)
+	}
+
+	var date time.Time
+	if feed.Date != "" {
+		var err error
+		if date, err = time.Parse(time.DateOnly, feed.Date); err != nil {
+			return nil, fmt.Errorf("invalid date %q", feed.Date)
+		}
+	}
+
+	var rates []Rate
+	for _, price := range feed.Metals {
+		m, ok := metals[strings.ToUpper(price.Code)]
+		if !ok {
+			continue
+		}
+		ounce, gram, err := perOunceAndGram(price)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", price.Code, err)
+		}
+		rates = append(rates,
+			metalRate(m.gramCode, m.name+" (GRAM)", "GRAM "+m.nameTR, gram, date),
+			metalRate(m.ounceCode, m.name+" (TROY OUNCE)", m.nameTR+" (ONS)", ounce, date),
+		)
+	}
+
+	if len(rates) == 0 {
+		return nil, errors.New("metals feed lists no gold or silver prices")
+	}
+	return rates, nil
+}
+
+// perOunceAndGram returns the [buying, selling] prices of one troy ounce and
+// of one gram.
+func perOunceAndGram(p metalPrice) (ounce, gram [2]decimal.Decimal, err error) {
+	prices := [2]decimal.Decimal{p.Buying, p.Selling}
+	for i, price := range prices {
+		switch strings.ToLower(p.Unit) {
+		case "ounce", "oz", "":
+			ounce[i] = price
+			gram[i], err = decimal.MulDiv(price, gramsPerOunceDen, gramsPerOunceNum, decimal.RoundHalfEven)
+		case "gram", "g":
+			gram[i] = price
+			ounce[i], err = decimal.MulDiv(price, gramsPerOunceNum, gramsPerOunceDen, decimal.RoundHalfEven)
+		default:
+			return ounce, gram, fmt.Errorf("unknown unit %q", p.Unit)
+		}
+		if err != nil {
+			return ounce, gram, err
+		}
+	}
+	return ounce, gram, nil
+}
+
+func metalRate(code currency.Code, name, nameTR string, prices [2]decimal.Decimal, date time.Time) Rate {
+	return Rate{
+		Code:         code,
+		Name:         name,
+		NameTR:       nameTR,
+		Unit:         1,
+		Buying:       prices[0],
+		Selling:      prices[1],
+		ForexBuying:  prices[0],
+		ForexSelling: prices[1],
+		Source:       SourceMetals,
+		Date:         date,
+	}
+}
@@ -0,0 +1,122 @@
+package fetcher
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetalsFetcher(t *testing.T) {
+	tests := []struct {
+		name         string
+		payload      string
+		want         map[string]string
+		expectErrSub string
+	}{
+		{
+			name:    "JSONPerOunce",
+			payload: `{"date": "2025-01-02", "metals": [{"code": "XAU", "unit": "ounce", "buying": "92650.12", "selling": 92790.4}, {"code": "XPT", "buying": "1"}]}`,
+			want: map[string]string{
+				// 92650.12 / 31.1034768 and 92790.4 / 31.1034768.
+				"GAU": "2978.770528/2983.280634",
+				"XAU": "92650.12/92790.4",
+			},
+		},
+		{
+			name: "XMLPerGram",
+			payload: `<?xml version="1.0" encoding="UTF-8"?>
+<Metals Date="2025-01-02">
+  <Metal Code="XAU" Unit="gram"><Buying>2978.81</Buying><Selling>2983.32</Selling></Metal>
+  <Metal Code="xag" Unit="g"><Buying>33.5</Buying><Selling>33.9</Selling></Metal>
+</Metals>`,
+			want: map[string]string{
+				"GAU": "2978.81/2983.32",
+				"XAU": "92651.347727/92791.624407",
+				"GAG": "33.5/33.9",
+				"XAG": "1041.966473/1054.407864",
+			},
+		},
+		{
+			name:         "UnknownUnit",
+			payload:      `{"metals": [{"code": "XAU", "unit": "kg", "buying": "1", "selling": "1"}]}`,
+			expectErrSub: `unknown unit "kg"`,
+		},
+		{
+			name:         "NoMetals",
+			payload:      `{"metals": []}`,
+			expectErrSub: "no gold or silver",
+		},
+		{
+			name:         "MalformedJSON",
+			payload:      `{"metals": [`,
+			expectErrSub: "parse JSON",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, tc.payload)
+			}))
+			defer ts.Close()
+
+			rates, err := NewMetalsFetcher(ts.URL, 2, 0, nil).FetchBulletin()
+
+			if tc.expectErrSub != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErrSub) {
+					t.Errorf("error = %v; want it to contain %q", err, tc.expectErrSub)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := map[string]string{}
+			for _, r := range rates {
+				got[r.Code.String()] = r.Buying.String() + "/" + r.Selling.String()
+				if r.Unit != 1 || r.Source != SourceMetals || !r.Date.Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)) {
+					t.Errorf("%s = %+v", r.Code, r)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Errorf("prices = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMetalsFetcherCache(t *testing.T) {
+	requests, fail := 0, false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if fail {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, `{"metals": [{"code": "XAU", "unit": "gram", "buying": "2978.81", "selling": "2983.32"}]}`)
+	}))
+	defer ts.Close()
+
+	now := time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC)
+	m := NewMetalsFetcher(ts.URL, 2, time.Minute, nil)
+	m.cache.now = func() time.Time { return now }
+
+	for range 2 {
+		if rate, err := m.FetchRate(); err != nil || rate.Code != CodeGoldGram || rate.Stale {
+			t.Fatalf("FetchRate = %+v, %v", rate, err)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("requests within TTL = %d; want 1", requests)
+	}
+
+	fail = true
+	now = now.Add(time.Minute)
+	if rate, err := m.FetchRate(); err != nil || !rate.Stale || rate.Selling.String() != "2983.32" {
+		t.Errorf("FetchRate after a failure = %+v, %v; want the last price, stale", rate, err)
+	}
+}
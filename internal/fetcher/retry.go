@@ -0,0 +1,147 @@
+package fetcher
+
+import (
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy bounds the attempts of a request and the jittered exponential
+// backoff between them.
+type RetryPolicy struct {
+	Attempts  int
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+}
+
+// backoff returns a random delay of up to BaseDelay*2^retry, capped at
+// MaxDelay ("full jitter").
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	ceiling := p.MaxDelay
+	if shifted := p.BaseDelay << retry; shifted > 0 && shifted < ceiling {
+		ceiling = shifted
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
+
+// RetryTransport retries idempotent requests that fail transiently and
+// guards the provider behind them with a circuit breaker.
+type RetryTransport struct {
+	base    http.RoundTripper
+	policy  RetryPolicy
+	breaker *Breaker
+	now     func() time.Time
+}
+
+// NewRetryTransport wraps base, or http.DefaultTransport when nil. A nil
+// breaker never opens.
+func NewRetryTransport(base http.RoundTripper, policy RetryPolicy, breaker *Breaker) *RetryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &RetryTransport{base: base, policy: policy, breaker: breaker, now: time.Now}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.breaker != nil {
+		if err := t.breaker.Allow(); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := t.roundTrip(req)
+
+	if t.breaker != nil {
+		t.breaker.Record(err == nil && !transientStatus(resp.StatusCode))
+	}
+	return resp, err
+}
+
+func (t *RetryTransport) roundTrip(req *http.Request) (*http.Response, error) {
+	attempts := max(t.policy.Attempts, 1)
+	if !replayable(req) {
+		attempts = 1
+	}
+
+	for retry := 0; ; retry++ {
+		resp, err := t.base.RoundTrip(req)
+		if retry == attempts-1 || req.Context().Err() != nil {
+			return resp, err
+		}
+		if err == nil && !transientStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		delay := t.policy.backoff(retry)
+		if err == nil {
+			if after, ok := retryAfter(resp.Header.Get("Retry-After"), t.now()); ok {
+				// Waiting longer than MaxDelay would hold up the caller; give up.
+				if after > t.policy.MaxDelay {
+					return resp, nil
+				}
+				delay = after
+			}
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+			resp.Body.Close()
+		}
+
+		if err := sleep(req, delay); err != nil {
+			return nil, err
+		}
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// replayable reports whether req is idempotent and can be sent again.
+func replayable(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+	default:
+		return false
+	}
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func transientStatus(code int) bool {
+	switch code {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header given in seconds or as a date.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+func sleep(req *http.Request, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
@@ -0,0 +1,105 @@
+package fetcher
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testPolicy = RetryPolicy{Attempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond}
+
+// statusServer answers with the given statuses in turn, then with 200.
+func statusServer(t *testing.T, header http.Header, statuses ...int) (*httptest.Server, *int) {
+	t.Helper()
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		for k, v := range header {
+			w.Header()[k] = v
+		}
+		if calls <= len(statuses) {
+			w.WriteHeader(statuses[calls-1])
+		}
+	}))
+	t.Cleanup(ts.Close)
+	return ts, &calls
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		header    http.Header
+		statuses  []int
+		wantCalls int
+		wantCode  int
+	}{
+		{name: "RetriesTransientStatus", method: http.MethodGet, statuses: []int{503, 502}, wantCalls: 3, wantCode: 200},
+		{name: "GivesUpAfterAttempts", method: http.MethodGet, statuses: []int{500, 500, 500, 500}, wantCalls: 3, wantCode: 500},
+		{name: "KeepsClientErrors", method: http.MethodGet, statuses: []int{404}, wantCalls: 1, wantCode: 404},
+		{name: "SkipsNonIdempotent", method: http.MethodPost, statuses: []int{503}, wantCalls: 1, wantCode: 503},
+		{name: "HonorsRetryAfter", method: http.MethodGet, header: http.Header{"Retry-After": {"0"}}, statuses: []int{429}, wantCalls: 2, wantCode: 200},
+		{name: "RetryAfterBeyondMaxDelay", method: http.MethodGet, header: http.Header{"Retry-After": {"120"}}, statuses: []int{429}, wantCalls: 1, wantCode: 429},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts, calls := statusServer(t, tc.header, tc.statuses...)
+			client := &http.Client{Transport: NewRetryTransport(nil, testPolicy, nil)}
+
+			req, _ := http.NewRequest(tc.method, ts.URL, strings.NewReader("body"))
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("Do: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tc.wantCode || *calls != tc.wantCalls {
+				t.Errorf("status %d after %d calls; want %d after %d", resp.StatusCode, *calls, tc.wantCode, tc.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryTransportOpensBreaker(t *testing.T) {
+	ts, calls := statusServer(t, nil, 500, 500, 500, 500, 500, 500)
+	breaker := NewBreaker(2, time.Hour)
+	client := &http.Client{Transport: NewRetryTransport(nil, testPolicy, breaker)}
+
+	for range 2 {
+		resp, err := client.Get(ts.URL)
+		if err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+		resp.Body.Close()
+	}
+	if breaker.State() != BreakerOpen {
+		t.Fatalf("breaker %s after two failed requests; want open", breaker.State())
+	}
+
+	if _, err := client.Get(ts.URL); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Get with an open circuit = %v; want %v", err, ErrCircuitOpen)
+	}
+	if *calls != 6 {
+		t.Errorf("server calls = %d; want 6", *calls)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC)
+	for value, want := range map[string]time.Duration{
+		"7":                             7 * time.Second,
+		"Thu, 02 Jan 2025 13:00:30 GMT": 30 * time.Second,
+		"Thu, 02 Jan 2025 12:00:00 GMT": 0,
+	} {
+		if got, ok := retryAfter(value, now); !ok || got != want {
+			t.Errorf("retryAfter(%q) = %v, %v; want %v", value, got, ok, want)
+		}
+	}
+	if _, ok := retryAfter("soon", now); ok {
+		t.Error("retryAfter accepted an invalid value")
+	}
+}
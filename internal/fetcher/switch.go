@@ -0,0 +1,45 @@
+package fetcher
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// Switch forwards to a fetcher that can be replaced while in use.
+type Switch struct {
+	current atomic.Pointer[RateFetcher]
+}
+
+func NewSwitch(f RateFetcher) *Switch {
+	s := &Switch{}
+	s.Store(f)
+	return s
+}
+
+func (s *Switch) Store(f RateFetcher) {
+	s.current.Store(&f)
+}
+
+func (s *Switch) Load() RateFetcher {
+	return *s.current.Load()
+}
+
+func (s *Switch) FetchRate() (*Rate, error) {
+	return s.Load().FetchRate()
+}
+
+func (s *Switch) FetchBulletin() ([]Rate, error) {
+	bf, ok := s.Load().(BulletinFetcher)
+	if !ok {
+		return nil, errors.New("no provider supports bulletins")
+	}
+	return bf.FetchBulletin()
+}
+
+// Health reports the providers' circuits when the current fetcher has any.
+func (s *Switch) Health() []ProviderHealth {
+	if h, ok := s.Load().(interface{ Health() []ProviderHealth }); ok {
+		return h.Health()
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package fetcher
+
+import (
+	"testing"
+
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+)
+
+func TestSwitch(t *testing.T) {
+	first := &fakeFetcher{rate: &Rate{Buying: decimal.FromInt(1)}, rates: []Rate{{Code: "USD"}}}
+	second := &fakeFetcher{rate: &Rate{Buying: decimal.FromInt(2)}, rates: []Rate{{Code: "EUR"}}}
+
+	s := NewSwitch(first)
+
+	rate, err := s.FetchRate()
+	if err != nil || !rate.Buying.Equal(decimal.FromInt(1)) {
+		t.Fatalf("FetchRate() = %+v, %v; want the first fetcher's rate", rate, err)
+	}
+
+	s.Store(second)
+
+	rate, err = s.FetchRate()
+	if err != nil || !rate.Buying.Equal(decimal.FromInt(2)) {
+		t.Fatalf("FetchRate() = %+v, %v; want the second fetcher's rate", rate, err)
+	}
+
+	rates, err := s.FetchBulletin()
+	if err != nil || len(rates) != 1 || rates[0].Code != "EUR" {
+		t.Fatalf("FetchBulletin() = %+v, %v; want the second fetcher's bulletin", rates, err)
+	}
+
+	s.Store(rateOnlyFetcher{})
+	if _, err := s.FetchBulletin(); err == nil {
+		t.Error("expected error for a fetcher without bulletins, got nil")
+	}
+}
@@ -1,72 +1,325 @@
 package fetcher
 
 import (
+	"bytes"
+	"cmp"
 	"encoding/xml"
 	"errors"
+	"expvar"
 	"fmt"
 	"io"
 	"net/http"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+	"golang.org/x/sync/singleflight"
+	"golang.org/x/text/encoding/charmap"
 )
 
+// TCMBClient fetches the TCMB bulletin. The last bulletin is kept for the
+// cache TTL; after that TCMB is asked with a conditional GET whether it
+// changed, concurrent callers share one request, and the cached bulletin is
+// served, marked stale, when TCMB can't be reached.
 type TCMBClient struct {
 	client *http.Client
 	url    string
+	ttl    time.Duration
+	now    func() time.Time
+
+	group  singleflight.Group
+	mu     sync.Mutex
+	cached *cachedBulletin
+}
+
+// cachedBulletin is the last parsed bulletin with the validators that tell
+// whether it is still current.
+type cachedBulletin struct {
+	rates        []Rate
+	etag         string
+	lastModified string
+	checkedAt    time.Time
 }
 
 type tcmbDate struct {
-	XMLName    xml.Name   `xml:"Tarih_Date"`
-	Currencies []currency `xml:"Currency"`
+	XMLName    xml.Name       `xml:"Tarih_Date"`
+	Tarih      string         `xml:"Tarih,attr"`
+	Date       string         `xml:"Date,attr"`
+	BultenNo   string         `xml:"Bulten_No,attr"`
+	Currencies []tcmbCurrency `xml:"Currency"`
 }
 
-type currency struct {
-	Unit         int     `xml:"Unit"`
-	CurrencyName string  `xml:"CurrencyName"`
-	ForexBuying  float64 `xml:"ForexBuying"`
-	ForexSelling float64 `xml:"ForexSelling"`
+// tcmbCurrency keeps the numbers as text so that one malformed entry can be
+// skipped instead of failing the whole bulletin.
+type tcmbCurrency struct {
+	Code            string `xml:"Kod,attr"`
+	Unit            string `xml:"Unit"`
+	Isim            string `xml:"Isim"`
+	CurrencyName    string `xml:"CurrencyName"`
+	ForexBuying     string `xml:"ForexBuying"`
+	ForexSelling    string `xml:"ForexSelling"`
+	BanknoteBuying  string `xml:"BanknoteBuying"`
+	BanknoteSelling string `xml:"BanknoteSelling"`
+	CrossRateUSD    string `xml:"CrossRateUSD"`
+	CrossRateOther  string `xml:"CrossRateOther"`
 }
 
-const TcmbUrl = "https://www.tcmb.gov.tr/kurlar/today.xml"
+// maxBulletinSize caps the bulletin body; a real one is about 10 KiB.
+const maxBulletinSize = 1 << 20
+
+// skippedCurrencies counts bulletin entries dropped as malformed, by code.
+var skippedCurrencies = expvar.NewMap("fetcher_tcmb_skipped_currencies_total")
 
-func NewTCMBClient(url string, timeoutSeconds int) *TCMBClient {
+const (
+	TcmbUrl = "https://www.tcmb.gov.tr/kurlar/today.xml"
+
+	SourceTCMB = "tcmb"
+)
+
+// NewTCMBClient sends requests through transport, or the default transport
+// when nil.
+func NewTCMBClient(url string, timeoutSeconds int, cacheTTL time.Duration, transport http.RoundTripper) *TCMBClient {
 	return &TCMBClient{
 		url: url,
 		client: &http.Client{
-			Timeout: time.Duration(timeoutSeconds) * time.Second,
+			Timeout:   time.Duration(timeoutSeconds) * time.Second,
+			Transport: transport,
 		},
+		ttl: cacheTTL,
+		now: time.Now,
 	}
 }
 
 func (c *TCMBClient) FetchRate() (*Rate, error) {
 	var result Rate
 
-	resp, err := c.client.Get(c.url)
+	rates, stale, err := c.fetch()
+	if err != nil {
+		return &result, err
+	}
+
+	for _, rate := range rates {
+		if rate.Code == currency.EUR {
+			result = rate
+			result.Stale = stale
+			return &result, nil
+		}
+	}
+
+	return &result, errors.New("EUR not found")
+}
+
+func (c *TCMBClient) FetchBulletin() ([]Rate, error) {
+	rates, stale, err := c.fetch()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]Rate, len(rates))
+	for i, rate := range rates {
+		rate.Stale = stale
+		out[i] = rate
+	}
+	return out, nil
+}
+
+// fetch returns the current bulletin and whether it is a stale copy served
+// because refreshing it failed. The returned slice is shared and must not be
+// modified.
+func (c *TCMBClient) fetch() ([]Rate, bool, error) {
+	c.mu.Lock()
+	cached := c.cached
+	c.mu.Unlock()
+
+	if cached != nil && c.now().Sub(cached.checkedAt) < c.ttl {
+		return cached.rates, false, nil
+	}
+
+	rates, err, _ := c.group.Do(c.url, func() (any, error) {
+		return c.refresh(cached)
+	})
+	if err != nil {
+		if cached != nil {
+			return cached.rates, true, nil
+		}
+		return nil, false, err
+	}
+	return rates.([]Rate), false, nil
+}
+
+// refresh downloads the bulletin, or confirms that cached is still current.
+func (c *TCMBClient) refresh(cached *cachedBulletin) ([]Rate, error) {
+	req, err := http.NewRequest(http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	if cached != nil {
+		if cached.etag != "" {
+			req.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	resp, err := c.client.Do(req)
 	if err != nil {
-		return &result, fmt.Errorf("http GET: %w", err)
+		return nil, fmt.Errorf("http GET: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		c.store(&cachedBulletin{
+			rates:        cached.rates,
+			etag:         cmp.Or(resp.Header.Get("ETag"), cached.etag),
+			lastModified: cmp.Or(resp.Header.Get("Last-Modified"), cached.lastModified),
+			checkedAt:    c.now(),
+		})
+		return cached.rates, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return &result, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+		return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBulletinSize+1))
 	if err != nil {
-		return &result, fmt.Errorf("read body: %w", err)
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	if len(body) > maxBulletinSize {
+		return nil, fmt.Errorf("bulletin exceeds %d bytes", maxBulletinSize)
 	}
 
-	var parsedBody tcmbDate
-	if err := xml.Unmarshal(body, &parsedBody); err != nil {
-		return &result, fmt.Errorf("parse XML: %w", err)
+	rates, err := parseBulletin(body)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, cur := range parsedBody.Currencies {
-		if cur.CurrencyName == "EURO" {
-			result.Buying = cur.ForexBuying
-			result.Selling = cur.ForexSelling
-			return &result, nil
+	c.store(&cachedBulletin{
+		rates:        rates,
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+		checkedAt:    c.now(),
+	})
+	return rates, nil
+}
+
+func (c *TCMBClient) store(cached *cachedBulletin) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cached = cached
+}
+
+// parseBulletin decodes a bulletin in UTF-8 or one of the Turkish legacy
+// charsets. Currencies with malformed numbers are skipped and counted; the
+// bulletin fails only when it lists currencies and none of them parse.
+func parseBulletin(body []byte) ([]Rate, error) {
+	var parsed tcmbDate
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = charsetReader
+	if err := decoder.Decode(&parsed); err != nil {
+		return nil, fmt.Errorf("parse XML: %w", err)
+	}
+
+	date := parsed.date()
+	rates := make([]Rate, 0, len(parsed.Currencies))
+	var errs []error
+	for _, cur := range parsed.Currencies {
+		rate, err := cur.toRate(date, parsed.BultenNo)
+		if err != nil {
+			skippedCurrencies.Add(cmp.Or(cur.Code, "unknown"), 1)
+			errs = append(errs, err)
+			continue
 		}
+		rates = append(rates, rate)
 	}
 
-	return &result, errors.New("EUR not found")
+	if len(rates) == 0 && len(errs) > 0 {
+		return nil, fmt.Errorf("parse XML: no valid currencies: %w", errors.Join(errs...))
+	}
+	return rates, nil
+}
+
+// charsetReader converts the legacy charsets TCMB has declared bulletins in
+// to UTF-8.
+func charsetReader(label string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(label) {
+	case "iso-8859-9", "iso8859-9", "latin5":
+		return charmap.ISO8859_9.NewDecoder().Reader(input), nil
+	case "windows-1254", "cp1254":
+		return charmap.Windows1254.NewDecoder().Reader(input), nil
+	case "us-ascii":
+		return input, nil
+	}
+	return nil, fmt.Errorf("unsupported charset %q", label)
+}
+
+// date is the day the bulletin is effective for, as midnight UTC. It reads
+// the English Date attribute and falls back to the Turkish Tarih one; the
+// zero time means the bulletin carries neither.
+func (d *tcmbDate) date() time.Time {
+	if t, err := time.Parse("01/02/2006", d.Date); err == nil {
+		return t
+	}
+	if t, err := time.Parse("02.01.2006", d.Tarih); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+func (cur tcmbCurrency) toRate(date time.Time, bulletinNo string) (Rate, error) {
+	rate := Rate{
+		Name:       strings.TrimSpace(cur.CurrencyName),
+		NameTR:     strings.TrimSpace(cur.Isim),
+		Source:     SourceTCMB,
+		Date:       date,
+		BulletinNo: bulletinNo,
+	}
+
+	// A missing code is tolerated; a malformed or unknown one skips the entry.
+	if kod := strings.TrimSpace(cur.Code); kod != "" {
+		code, err := currency.Parse(kod)
+		if err != nil {
+			return Rate{}, err
+		}
+		rate.Code = code
+	}
+
+	if unit := strings.TrimSpace(cur.Unit); unit != "" {
+		n, err := strconv.Atoi(unit)
+		if err != nil || n < 1 {
+			return Rate{}, fmt.Errorf("%s: invalid unit %q", rate.Code, unit)
+		}
+		rate.Unit = n
+	}
+
+	for _, field := range []struct {
+		name  string
+		value string
+		dst   *decimal.Decimal
+	}{
+		{"ForexBuying", cur.ForexBuying, &rate.ForexBuying},
+		{"ForexSelling", cur.ForexSelling, &rate.ForexSelling},
+		{"BanknoteBuying", cur.BanknoteBuying, &rate.BanknoteBuying},
+		{"BanknoteSelling", cur.BanknoteSelling, &rate.BanknoteSelling},
+		{"CrossRateUSD", cur.CrossRateUSD, &rate.CrossRateUSD},
+		{"CrossRateOther", cur.CrossRateOther, &rate.CrossRateOther},
+	} {
+		// TCMB leaves prices it doesn't quote empty, e.g. SDR selling.
+		value := strings.TrimSpace(field.value)
+		if value == "" {
+			continue
+		}
+		d, err := decimal.Parse(value)
+		if err != nil || d.Sign() < 0 {
+			return Rate{}, fmt.Errorf("%s: invalid %s %q", rate.Code, field.name, value)
+		}
+		*field.dst = d
+	}
+
+	rate.Buying, rate.Selling = rate.ForexBuying, rate.ForexSelling
+	return rate, nil
 }
@@ -1,22 +1,117 @@
 package fetcher
 
 import (
+	"flag"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+	"github.com/akyTheDev/currency-bot/internal/httpfixture"
 )
 
+var record = flag.String("record", "", "save the live TCMB bulletin to this file, e.g. testdata/tcmb/2025-01-02.http")
+
+// TestRecordBulletin captures a fixture for TestFetchBulletinFixtures:
+//
+//	go test ./internal/fetcher -run TestRecordBulletin -record testdata/tcmb/<date>.http
+func TestRecordBulletin(t *testing.T) {
+	if *record == "" {
+		t.Skip("no -record file given")
+	}
+
+	rates, err := NewTCMBClient(TcmbUrl, 30, 0, &httpfixture.Recorder{Path: *record}).FetchBulletin()
+	if err != nil {
+		t.Fatalf("FetchBulletin: %v", err)
+	}
+	t.Logf("recorded %d rates to %s", len(rates), *record)
+}
+
+// TestFetchBulletinFixtures parses bulletins as TCMB serves them, replayed
+// from testdata/tcmb.
+func TestFetchBulletinFixtures(t *testing.T) {
+	tests := []struct {
+		file       string
+		date       time.Time
+		bulletinNo string
+		usdBuying  string
+		eurSelling string
+	}{
+		{
+			file: "2025-01-02.http",
+			date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), bulletinNo: "2025/1",
+			usdBuying: "35.2763", eurSelling: "36.5873",
+		},
+		{
+			// No bulletin is published on holidays; the last one is served.
+			file: "holiday-2025-01-01.http",
+			date: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), bulletinNo: "2024/247",
+			usdBuying: "35.2233", eurSelling: "36.5323",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.file, func(t *testing.T) {
+			client := NewTCMBClient(TcmbUrl, 2, 0, httpfixture.Replay(filepath.Join("testdata", "tcmb", tc.file)))
+
+			rates, err := client.FetchBulletin()
+			if err != nil {
+				t.Fatalf("FetchBulletin: %v", err)
+			}
+			if len(rates) != 23 {
+				t.Fatalf("got %d rates, want 23", len(rates))
+			}
+
+			byCode := map[currency.Code]Rate{}
+			for _, r := range rates {
+				if !r.Date.Equal(tc.date) || r.BulletinNo != tc.bulletinNo || r.Source != SourceTCMB || r.Stale {
+					t.Errorf("%s bulletin = %v %q %q stale=%v", r.Code, r.Date, r.BulletinNo, r.Source, r.Stale)
+				}
+				if r.Unit < 1 || r.Name == "" || r.NameTR == "" || r.Buying.IsZero() {
+					t.Errorf("%s incomplete: %+v", r.Code, r)
+				}
+				byCode[r.Code] = r
+			}
+
+			if usd := byCode["USD"]; usd.Buying.String() != tc.usdBuying || usd.NameTR != "ABD DOLARI" || !usd.CrossRateUSD.IsZero() {
+				t.Errorf("USD = %+v", usd)
+			}
+			if jpy := byCode["JPY"]; jpy.Unit != 100 || jpy.CrossRateUSD.String() != "157.23" {
+				t.Errorf("JPY = %+v; want unit 100", jpy)
+			}
+			// SDR carries only a forex buying rate, and untrimmed names.
+			xdr := byCode["XDR"]
+			if !xdr.Selling.IsZero() || !xdr.BanknoteBuying.IsZero() || xdr.Name != "SPECIAL DRAWING RIGHT (SDR)" || xdr.NameTR != "ÖZEL ÇEKME HAKKI (SDR)" {
+				t.Errorf("XDR = %+v", xdr)
+			}
+			if aed := byCode["AED"].WithPrices(PriceBanknote); !aed.Buying.Equal(aed.ForexBuying) {
+				t.Errorf("AED without banknote prices = %v; want forex", aed.Buying)
+			}
+
+			eur, err := client.FetchRate()
+			if err != nil || eur.Code != "EUR" || eur.Selling.String() != tc.eurSelling || eur.CrossRateOther.String() != "1.0353" {
+				t.Errorf("FetchRate = %+v, %v", eur, err)
+			}
+		})
+	}
+}
+
 func TestFetchRate(t *testing.T) {
 	tests := []struct {
 		name            string
 		xmlPayload      string
 		statusCode      int
 		timeoutSec      int
-		expectedSelling float64
-		expectedBuying  float64
+		expectedSelling decimal.Decimal
+		expectedBuying  decimal.Decimal
 		expectErrSub    string
 		delayResponse   time.Duration
 	}{
@@ -24,21 +119,21 @@ func TestFetchRate(t *testing.T) {
 			name: "ValidXML",
 			xmlPayload: `
 <Tarih_Date>
-  <Currency>
+  <Currency Kod="EUR">
 	<CurrencyName>EURO</CurrencyName>
     <ForexSelling>22.2222</ForexSelling>
     <ForexBuying>21.2222</ForexBuying>
   </Currency>
-  <Currency>
-	<CurrencyName>USD</CurrencyName>
+  <Currency Kod="USD">
+	<CurrencyName>US DOLLAR</CurrencyName>
     <ForexSelling>20.1000</ForexSelling>
     <ForexBuying>19.1000</ForexBuying>
   </Currency>
 </Tarih_Date>`,
 			statusCode:      http.StatusOK,
 			timeoutSec:      2,
-			expectedSelling: 22.2222,
-			expectedBuying:  21.2222,
+			expectedSelling: decimal.MustParse("22.2222"),
+			expectedBuying:  decimal.MustParse("21.2222"),
 		},
 		{
 			name:         "Non200Status",
@@ -100,6 +195,8 @@ func TestFetchRate(t *testing.T) {
 			client := NewTCMBClient(
 				ts.URL,
 				tc.timeoutSec,
+				0,
+				nil,
 			)
 
 			rate, err := client.FetchRate()
@@ -108,7 +205,7 @@ func TestFetchRate(t *testing.T) {
 				if err != nil {
 					t.Fatalf("unexpected error: %v", err)
 				}
-				if rate.Selling != tc.expectedSelling || rate.Buying != tc.expectedBuying {
+				if !rate.Selling.Equal(tc.expectedSelling) || !rate.Buying.Equal(tc.expectedBuying) {
 					t.Errorf("rate = %v; want %v", rate, Rate{Buying: tc.expectedBuying, Selling: tc.expectedSelling})
 				}
 			} else {
@@ -122,3 +219,317 @@ func TestFetchRate(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchBulletin(t *testing.T) {
+	tests := []struct {
+		name         string
+		xmlPayload   string
+		statusCode   int
+		expected     []Rate
+		expectErrSub string
+	}{
+		{
+			name: "ValidXML",
+			xmlPayload: `
+<Tarih_Date Tarih="02.01.2025" Date="01/02/2025" Bulten_No="2025/1">
+  <Currency CrossOrder="0" Kod="USD" CurrencyCode="USD">
+    <Unit>1</Unit>
+    <Isim>ABD DOLARI</Isim>
+    <CurrencyName>US DOLLAR</CurrencyName>
+    <ForexBuying>19.1000</ForexBuying>
+    <ForexSelling>20.1000</ForexSelling>
+    <BanknoteBuying>19.0500</BanknoteBuying>
+    <BanknoteSelling>20.2000</BanknoteSelling>
+    <CrossRateUSD/>
+    <CrossRateOther/>
+  </Currency>
+  <Currency CrossOrder="9" Kod="JPY" CurrencyCode="JPY">
+    <Unit>100</Unit>
+    <Isim>JAPON YENİ</Isim>
+    <CurrencyName>JAPENESE YEN</CurrencyName>
+    <ForexBuying>22.5000</ForexBuying>
+    <ForexSelling>22.6500</ForexSelling>
+    <BanknoteBuying></BanknoteBuying>
+    <BanknoteSelling></BanknoteSelling>
+    <CrossRateUSD>157.23</CrossRateUSD>
+    <CrossRateOther/>
+  </Currency>
+</Tarih_Date>`,
+			statusCode: http.StatusOK,
+			expected: []Rate{
+				{
+					Code: "USD", Name: "US DOLLAR", NameTR: "ABD DOLARI", Unit: 1,
+					Buying: decimal.MustParse("19.1"), Selling: decimal.MustParse("20.1"),
+					ForexBuying: decimal.MustParse("19.1"), ForexSelling: decimal.MustParse("20.1"),
+					BanknoteBuying: decimal.MustParse("19.05"), BanknoteSelling: decimal.MustParse("20.2"),
+					Source: SourceTCMB, Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), BulletinNo: "2025/1",
+				},
+				{
+					Code: "JPY", Name: "JAPENESE YEN", NameTR: "JAPON YENİ", Unit: 100,
+					Buying: decimal.MustParse("22.5"), Selling: decimal.MustParse("22.65"),
+					ForexBuying: decimal.MustParse("22.5"), ForexSelling: decimal.MustParse("22.65"),
+					CrossRateUSD: decimal.MustParse("157.23"),
+					Source:       SourceTCMB, Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), BulletinNo: "2025/1",
+				},
+			},
+		},
+		{
+			name: "TurkishDateOnly",
+			xmlPayload: `
+<Tarih_Date Tarih="03.01.2025">
+  <Currency Kod="USD"><Unit>1</Unit><ForexBuying>19.1</ForexBuying><ForexSelling>20.1</ForexSelling></Currency>
+</Tarih_Date>`,
+			statusCode: http.StatusOK,
+			expected: []Rate{{
+				Code: "USD", Unit: 1,
+				Buying: decimal.MustParse("19.1"), Selling: decimal.MustParse("20.1"),
+				ForexBuying: decimal.MustParse("19.1"), ForexSelling: decimal.MustParse("20.1"),
+				Source: SourceTCMB, Date: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+			}},
+		},
+		{
+			name:         "Non200Status",
+			statusCode:   http.StatusBadGateway,
+			expectErrSub: "unexpected status",
+		},
+		{
+			name:         "MalformedXML",
+			xmlPayload:   `<Tarih_Date><Currency Kod="USD">`,
+			statusCode:   http.StatusOK,
+			expectErrSub: "parse XML",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.statusCode)
+				fmt.Fprint(w, tc.xmlPayload)
+			}))
+			defer ts.Close()
+
+			rates, err := NewTCMBClient(ts.URL, 2, 0, nil).FetchBulletin()
+
+			if tc.expectErrSub != "" {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				if !strings.Contains(err.Error(), tc.expectErrSub) {
+					t.Errorf("error %q does not contain %q", err.Error(), tc.expectErrSub)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(rates) != len(tc.expected) {
+				t.Fatalf("got %d rates, want %d", len(rates), len(tc.expected))
+			}
+			for i := range rates {
+				if rates[i] != tc.expected[i] {
+					t.Errorf("rates[%d] = %+v; want %+v", i, rates[i], tc.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRateWithPrices(t *testing.T) {
+	rate := Rate{
+		Code:   "USD",
+		Buying: decimal.MustParse("19.1"), Selling: decimal.MustParse("20.1"),
+		ForexBuying: decimal.MustParse("19.1"), ForexSelling: decimal.MustParse("20.1"),
+		BanknoteBuying: decimal.MustParse("19.05"), BanknoteSelling: decimal.MustParse("20.2"),
+	}
+
+	banknote := rate.WithPrices(PriceBanknote)
+	if banknote.Buying.String() != "19.05" || banknote.Selling.String() != "20.2" {
+		t.Errorf("banknote prices = %v/%v", banknote.Buying, banknote.Selling)
+	}
+	if forex := banknote.WithPrices(PriceForex); forex.Buying.String() != "19.1" || forex.Selling.String() != "20.1" {
+		t.Errorf("forex prices = %v/%v", forex.Buying, forex.Selling)
+	}
+
+	rate.BanknoteBuying, rate.BanknoteSelling = decimal.Decimal{}, decimal.Decimal{}
+	if got := WithPrices([]Rate{rate}, PriceBanknote); got[0].Buying.String() != "19.1" {
+		t.Errorf("rate without banknote prices = %v; want forex", got[0].Buying)
+	}
+}
+
+func TestTCMBClientCache(t *testing.T) {
+	var requests []*http.Request
+	fail := false
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		switch {
+		case fail:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Header.Get("If-None-Match") == `"v1"`:
+			w.WriteHeader(http.StatusNotModified)
+		default:
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Last-Modified", "Thu, 02 Jan 2025 12:30:00 GMT")
+			fmt.Fprint(w, `<Tarih_Date><Currency Kod="USD"><Unit>1</Unit><ForexBuying>19.1</ForexBuying></Currency></Tarih_Date>`)
+		}
+	}))
+	defer ts.Close()
+
+	now := time.Date(2025, 1, 2, 13, 0, 0, 0, time.UTC)
+	client := NewTCMBClient(ts.URL, 2, time.Minute, nil)
+	client.now = func() time.Time { return now }
+
+	fetch := func() []Rate {
+		t.Helper()
+		rates, err := client.FetchBulletin()
+		if err != nil || len(rates) != 1 || rates[0].Buying.String() != "19.1" {
+			t.Fatalf("FetchBulletin = %+v, %v", rates, err)
+		}
+		return rates
+	}
+
+	fetch()
+	fetch()
+	if len(requests) != 1 {
+		t.Fatalf("requests within TTL = %d; want 1", len(requests))
+	}
+
+	now = now.Add(2 * time.Minute)
+	if rates := fetch(); rates[0].Stale {
+		t.Error("revalidated bulletin marked stale")
+	}
+	if len(requests) != 2 || requests[1].Header.Get("If-None-Match") != `"v1"` || requests[1].Header.Get("If-Modified-Since") == "" {
+		t.Fatalf("revalidation request headers = %v", requests[len(requests)-1].Header)
+	}
+
+	fail = true
+	now = now.Add(2 * time.Minute)
+	if rates := fetch(); !rates[0].Stale {
+		t.Error("bulletin served after a failed refresh is not marked stale")
+	}
+
+	if _, err := NewTCMBClient(ts.URL, 2, time.Minute, nil).FetchBulletin(); err == nil {
+		t.Error("failure without a cached bulletin returned no error")
+	}
+}
+
+func TestTCMBClientCoalescesRequests(t *testing.T) {
+	var requests atomic.Int32
+	arrived, release := make(chan struct{}, 1), make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		arrived <- struct{}{}
+		<-release
+		fmt.Fprint(w, `<Tarih_Date><Currency Kod="USD"><Unit>1</Unit></Currency></Tarih_Date>`)
+	}))
+	defer ts.Close()
+
+	client := NewTCMBClient(ts.URL, 2, time.Minute, nil)
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.FetchBulletin(); err != nil {
+				t.Errorf("FetchBulletin: %v", err)
+			}
+		}()
+	}
+
+	<-arrived
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d; want 1 shared by all callers", got)
+	}
+}
+
+func TestParseBulletin(t *testing.T) {
+	t.Run("SkipsMalformedCurrencies", func(t *testing.T) {
+		before := skippedCount("GBP")
+		rates, err := parseBulletin([]byte(`<Tarih_Date Tarih="02.01.2025">
+  <Currency Kod="USD"><Unit>1</Unit><ForexBuying>35.2763</ForexBuying><ForexSelling></ForexSelling></Currency>
+  <Currency Kod="GBP"><Unit>1</Unit><ForexBuying>44,1</ForexBuying></Currency>
+  <Currency Kod="JPY"><Unit>100</Unit><ForexBuying> 22.4361 </ForexBuying></Currency>
+  <Currency Kod="U$D"><Unit>1</Unit><ForexBuying>35.2763</ForexBuying></Currency>
+</Tarih_Date>`))
+		if err != nil {
+			t.Fatalf("parseBulletin: %v", err)
+		}
+		if len(rates) != 2 || rates[0].Code != "USD" || !rates[0].Selling.IsZero() || rates[1].Code != "JPY" || rates[1].Buying.String() != "22.4361" {
+			t.Errorf("rates = %+v; want USD and JPY", rates)
+		}
+		if got := skippedCount("GBP") - before; got != 1 {
+			t.Errorf("skipped GBP counted %d times; want 1", got)
+		}
+	})
+
+	t.Run("FailsWithoutValidCurrencies", func(t *testing.T) {
+		_, err := parseBulletin([]byte(`<Tarih_Date><Currency Kod="USD"><Unit>one</Unit></Currency></Tarih_Date>`))
+		if err == nil || !strings.Contains(err.Error(), `USD: invalid unit "one"`) {
+			t.Errorf("error = %v; want the invalid unit", err)
+		}
+	})
+
+	for label, isim := range map[string][]byte{
+		// "TÜRK LİRASI" in each charset.
+		"ISO-8859-9":   {'T', 0xdc, 'R', 'K', ' ', 'L', 0xdd, 'R', 'A', 'S', 'I'},
+		"windows-1254": {'T', 0xdc, 'R', 'K', ' ', 'L', 0xdd, 'R', 'A', 'S', 'I'},
+	} {
+		t.Run(label, func(t *testing.T) {
+			body := append([]byte(`<?xml version="1.0" encoding="`+label+`"?><Tarih_Date><Currency Kod="TRY"><Isim>`), isim...)
+			body = append(body, `</Isim></Currency></Tarih_Date>`...)
+
+			rates, err := parseBulletin(body)
+			if err != nil || len(rates) != 1 || rates[0].NameTR != "TÜRK LİRASI" {
+				t.Errorf("parseBulletin = %+v, %v", rates, err)
+			}
+		})
+	}
+}
+
+func skippedCount(code string) int64 {
+	if v, ok := skippedCurrencies.Get(code).(interface{ Value() int64 }); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func TestFetchBulletinBodyLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<Tarih_Date>", strings.Repeat(" ", maxBulletinSize), "</Tarih_Date>")
+	}))
+	defer ts.Close()
+
+	if _, err := NewTCMBClient(ts.URL, 2, 0, nil).FetchBulletin(); err == nil || !strings.Contains(err.Error(), "exceeds") {
+		t.Errorf("error = %v; want the size limit", err)
+	}
+}
+
+func FuzzParseBulletin(f *testing.F) {
+	for _, file := range []string{"2025-01-02.http", "holiday-2025-01-01.http"} {
+		resp, err := httpfixture.Replay(filepath.Join("testdata", "tcmb", file)).RoundTrip(httptest.NewRequest(http.MethodGet, TcmbUrl, nil))
+		if err != nil {
+			f.Fatalf("replay %s: %v", file, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		f.Add(body)
+	}
+	f.Add([]byte(`<?xml version="1.0" encoding="ISO-8859-9"?><Tarih_Date><Currency Kod="USD"><Unit>1</Unit><ForexBuying>1e3</ForexBuying></Currency></Tarih_Date>`))
+	f.Add([]byte(`<Tarih_Date><Currency><ForexSelling>-1</ForexSelling></Currency><Currency/></Tarih_Date>`))
+
+	f.Fuzz(func(t *testing.T, body []byte) {
+		rates, err := parseBulletin(body)
+		if err != nil {
+			return
+		}
+		for _, r := range rates {
+			if r.Unit < 0 || r.Buying.Sign() < 0 || r.Selling.Sign() < 0 || r.Source != SourceTCMB {
+				t.Errorf("parseBulletin accepted %+v", r)
+			}
+		}
+	})
+}
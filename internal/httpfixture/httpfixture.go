@@ -0,0 +1,69 @@
+// Package httpfixture records HTTP responses to files and replays them, so
+// tests can run against real provider responses without network access.
+package httpfixture
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+)
+
+// Recorder sends requests through Transport, or the default transport when
+// nil, and saves each response with its headers to Path.
+type Recorder struct {
+	Transport http.RoundTripper
+	Path      string
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// DumpResponse reads the body and puts an unread copy back.
+	dump, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("dump response: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0o755); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("create fixture directory: %w", err)
+	}
+	if err := os.WriteFile(r.Path, dump, 0o644); err != nil {
+		resp.Body.Close()
+		return nil, fmt.Errorf("write fixture: %w", err)
+	}
+
+	return resp, nil
+}
+
+// Replay answers every request with the response saved at path.
+func Replay(path string) http.RoundTripper {
+	return replay(path)
+}
+
+type replay string
+
+func (path replay) RoundTrip(req *http.Request) (*http.Response, error) {
+	content, err := os.ReadFile(string(path))
+	if err != nil {
+		return nil, fmt.Errorf("read fixture: %w", err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(content)), req)
+	if err != nil {
+		return nil, fmt.Errorf("parse fixture %s: %w", path, err)
+	}
+	return resp, nil
+}
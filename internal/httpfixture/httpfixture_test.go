@@ -0,0 +1,47 @@
+package httpfixture
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(http.StatusTeapot)
+		fmt.Fprint(w, "<Tarih_Date/>")
+	}))
+	defer ts.Close()
+
+	path := filepath.Join(t.TempDir(), "nested", "bulletin.http")
+
+	// The replay must see what the recording saved, so order matters.
+	for _, step := range []struct {
+		name      string
+		transport http.RoundTripper
+	}{
+		{"Record", &Recorder{Path: path}},
+		{"Replay", Replay(path)},
+	} {
+		t.Run(step.name, func(t *testing.T) {
+			resp, err := (&http.Client{Transport: step.transport}).Get(ts.URL)
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			defer resp.Body.Close()
+
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != http.StatusTeapot || resp.Header.Get("ETag") != `"v1"` || string(body) != "<Tarih_Date/>" {
+				t.Errorf("response = %d %v %q", resp.StatusCode, resp.Header, body)
+			}
+		})
+	}
+
+	if _, err := Replay(filepath.Join(t.TempDir(), "missing.http")).RoundTrip(httptest.NewRequest(http.MethodGet, "/", nil)); err == nil {
+		t.Error("replaying a missing fixture returned no error")
+	}
+}
@@ -0,0 +1,107 @@
+package i18n
+
+var en = map[string]message{
+	UnknownCommand: {other: "Unknown command. Send /help to see what I can do."},
+	ErrorGeneric:   {other: "An unexpected error occured. Please try again later."},
+	SlowDown:       {other: "Slow down! You are sending commands too fast; I will ignore them for a moment."},
+
+	HelpRegister:  {other: "Register to receive hourly EUR→TRY updates"},
+	HelpDelete:    {other: "Unregister from receiving updates"},
+	HelpLanguage:  {other: "Change the bot language"},
+	HelpSubscribe: {other: "Choose the currencies you receive"},
+	HelpRate:      {other: "Show current rates, e.g. /rate USD"},
+	HelpHelp:      {other: "List the available commands"},
+	HelpHeader:    {other: "Available commands:"},
+	HelpStats:     {other: "Subscriber and delivery statistics"},
+	HelpBroadcast: {other: "Send a message to all subscribers"},
+	HelpFetchNow:  {other: "Notify subscribers immediately"},
+	HelpUser:      {other: "Inspect a subscriber"},
+	HelpChannel:   {other: "Send updates to a channel you administer"},
+	HelpLive:      {other: "Keep one pinned, live-updated rate message"},
+	HelpDigest:    {other: "Get a daily or weekly digest"},
+	HelpPrices:    {other: "Choose forex or banknote rates"},
+
+	UsageRate:     {other: "Usage: /rate [CODE ...], at most 5 codes, e.g. /rate USD EUR"},
+	UsageLanguage: {other: "Usage: /language [en|tr]"},
+	UsageChannel:  {other: "Usage: /channel @channel [delete]"},
+	UsageLive:     {other: "Usage: /live on|off"},
+	UsageDigest:   {other: "Usage: /digest [daily|weekly|off]"},
+	UsagePrices:   {other: "Usage: /prices [forex|banknote]"},
+
+	RegisterSuccess: {other: "✅ You have been registered! You will receive hourly EUR→TRY updates."},
+	RegisterAlready: {other: "You are already registered!"},
+	DeleteSuccess:   {other: "🗑️ You have been unregistered. You will no longer receive updates."},
+	DeleteMissing:   {other: "You are not registered!"},
+	DeleteConfirm:   {other: "Do you really want to unregister? You will no longer receive updates."},
+	DeleteCancelled: {other: "Unregistering cancelled."},
+
+	SubscribePrompt:  {other: "Tap a currency to subscribe or unsubscribe. Without a selection you receive EUR."},
+	SubscribeAdded:   {other: "%s: subscribed"},
+	SubscribeRemoved: {other: "%s: unsubscribed"},
+
+	RateMessage:         {other: "%s→TRY Selling: %s Buying: %s (at %s)"},
+	RateUnknownCurrency: {other: "Unknown currency %q."},
+	RateRefreshed:       {other: "Rates updated"},
+
+	ButtonYes:     {other: "Yes, unregister"},
+	ButtonNo:      {other: "No"},
+	ButtonRefresh: {other: "🔄 Refresh"},
+
+	CallbackInvalid: {other: "This button is no longer valid."},
+
+	InlineTitle:    {other: "%s %s = %s %s"},
+	InlinePrices:   {other: "%s buying %s, selling %s"},
+	InlineBulletin: {other: "Rates of %s"},
+
+	LanguageCurrent:     {other: "Current language: English (%s).\nUse /language en or /language tr to change it."},
+	LanguageChanged:     {other: "✅ Language set to English."},
+	LanguageUnsupported: {other: "Unsupported language %q. Available: %s."},
+
+	GroupAdminOnly: {other: "⛔ Only group administrators can change this chat's updates."},
+
+	ChannelNotFound:    {other: "Channel %s not found. Add me to the channel as an administrator first."},
+	ChannelBotNotAdmin: {other: "I need to be an administrator allowed to post messages in %s."},
+	ChannelNotAdmin:    {other: "⛔ Only administrators of %s can change its updates."},
+	ChannelRegistered:  {other: "✅ %s will receive rate updates."},
+	ChannelAlready:     {other: "%s is already registered."},
+	ChannelRemoved:     {other: "🗑️ %s will no longer receive updates."},
+	ChannelMissing:     {other: "%s is not registered."},
+
+	LiveHeader:        {other: "📌 Live rates"},
+	LiveOff:           {other: "Live mode is off. Scheduled updates arrive as new messages again."},
+	LiveNotEnabled:    {other: "Live mode is not on."},
+	LiveNotRegistered: {other: "Register with /register first to use live mode."},
+
+	DigestDailyOn:      {other: "🗞️ You get a digest every morning."},
+	DigestWeeklyOn:     {other: "🗞️ You get a digest every Monday morning."},
+	DigestNone:         {other: "You get no digest. Use /digest daily or /digest weekly to get one."},
+	DigestOff:          {other: "Digest turned off."},
+	DigestDailyHeader:  {other: "🗞️ Daily digest, close of %s"},
+	DigestWeeklyHeader: {other: "🗞️ Weekly digest, close of %s"},
+	DigestLine:         {other: "%s %s\n  Day %s · Week %s\n  7 days %s – %s %s"},
+	DigestPercent:      {other: "%s%%"},
+	DigestNoChange:     {other: "n/a"},
+
+	PricesForex:    {other: "💱 You get forex rates, which apply to transfers. Use /prices banknote for cash rates."},
+	PricesBanknote: {other: "💵 You get banknote rates, which apply to cash. Use /prices forex for transfer rates."},
+
+	AdminUnauthorized:     {other: "⛔ You are not allowed to use this command."},
+	AdminStats:            {other: "📊 Stats\nSubscribers: %s\nActive: %s\nBlocked: %s\nMessages sent today: %s"},
+	AdminBroadcastUsage:   {other: "Usage: /broadcast <text>"},
+	AdminBroadcastNone:    {other: "No pending broadcast."},
+	AdminBroadcastDiscard: {other: "Broadcast discarded."},
+	AdminBroadcastPreview: {
+		one:   "📣 This message will be sent to up to %s subscriber:\n\n%s\n\nSend /broadcast confirm to send it or /broadcast cancel to discard it.",
+		other: "📣 This message will be sent to up to %s subscribers:\n\n%s\n\nSend /broadcast confirm to send it or /broadcast cancel to discard it.",
+	},
+	AdminBroadcastSent: {
+		one:   "✅ Broadcast sent to %s subscriber.",
+		other: "✅ Broadcast sent to %s subscribers.",
+	},
+	AdminFetchNow:         {other: "🔄 Notification triggered."},
+	AdminUserUsage:        {other: "Usage: /user <chat_id>"},
+	AdminUserNotFound:     {other: "No subscriber with chat_id=%d."},
+	AdminUserInfo:         {other: "👤 User %d\nID: %d\nRegistered: %s\nStatus: %s"},
+	AdminUserActive:       {other: "active"},
+	AdminUserBlockedSince: {other: "blocked since %s"},
+}
@@ -0,0 +1,137 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+)
+
+const (
+	EN = "en"
+	TR = "tr"
+
+	Default = EN
+)
+
+type message struct {
+	one   string
+	other string
+}
+
+var catalogs = map[string]map[string]message{
+	EN: en,
+	TR: tr,
+}
+
+func Supported() []string {
+	return []string{EN, TR}
+}
+
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Match maps a Telegram/IETF language code such as "tr-TR" onto one of the
+// supported locales.
+func Match(code string, supported []string) (string, bool) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if i := strings.IndexAny(code, "-_"); i >= 0 {
+		code = code[:i]
+	}
+	for _, locale := range supported {
+		if locale == code {
+			return locale, true
+		}
+	}
+	return "", false
+}
+
+func lookup(locale, key string) (message, bool) {
+	if m, ok := catalogs[locale][key]; ok {
+		return m, true
+	}
+	m, ok := catalogs[Default][key]
+	return m, ok
+}
+
+func T(locale, key string, args ...any) string {
+	m, ok := lookup(locale, key)
+	if !ok {
+		return key
+	}
+	if len(args) == 0 {
+		return m.other
+	}
+	return fmt.Sprintf(m.other, args...)
+}
+
+// N picks the singular or plural form of key for n. The count is passed to
+// the message as its first argument, formatted for the locale.
+func N(locale, key string, n int, args ...any) string {
+	m, ok := lookup(locale, key)
+	if !ok {
+		return key
+	}
+	format := m.other
+	if n == 1 && m.one != "" {
+		format = m.one
+	}
+	return fmt.Sprintf(format, append([]any{FormatInt(locale, n)}, args...)...)
+}
+
+func separators(locale string) (point, thousands string) {
+	if locale == TR {
+		return ",", "."
+	}
+	return ".", ","
+}
+
+// FormatNumber formats value with decimals fractional digits, rounding half
+// up, and the separators of the locale.
+func FormatNumber(locale string, value decimal.Decimal, decimals int) string {
+	point, thousands := separators(locale)
+
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+		value = value.Abs()
+	}
+
+	s := value.StringFixed(decimals)
+	intPart, fracPart, _ := strings.Cut(s, ".")
+
+	out := sign + groupThousands(intPart, thousands)
+	if fracPart != "" {
+		out += point + fracPart
+	}
+	return out
+}
+
+func FormatInt(locale string, n int) string {
+	_, thousands := separators(locale)
+	if n < 0 {
+		return "-" + groupThousands(strconv.Itoa(-n), thousands)
+	}
+	return groupThousands(strconv.Itoa(n), thousands)
+}
+
+func groupThousands(digits, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+)
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		locale   string
+		value    string
+		decimals int
+		expected string
+	}{
+		{EN, "36.1234", 4, "36.1234"},
+		{TR, "36.1234", 4, "36,1234"},
+		{EN, "1234567.891", 2, "1,234,567.89"},
+		{TR, "1234567.891", 2, "1.234.567,89"},
+		{TR, "-0.5", 1, "-0,5"},
+		{EN, "999", 0, "999"},
+	}
+
+	for _, tc := range tests {
+		if got := FormatNumber(tc.locale, decimal.MustParse(tc.value), tc.decimals); got != tc.expected {
+			t.Errorf("FormatNumber(%s, %s, %d) = %q; want %q", tc.locale, tc.value, tc.decimals, got, tc.expected)
+		}
+	}
+}
+
+func TestFormatInt(t *testing.T) {
+	if got := FormatInt(TR, 12345); got != "12.345" {
+		t.Errorf("FormatInt(tr) = %q; want %q", got, "12.345")
+	}
+	if got := FormatInt(EN, -1000); got != "-1,000" {
+		t.Errorf("FormatInt(en) = %q; want %q", got, "-1,000")
+	}
+}
+
+func TestN(t *testing.T) {
+	tests := []struct {
+		locale   string
+		n        int
+		expected string
+	}{
+		{EN, 1, "✅ Broadcast sent to 1 subscriber."},
+		{EN, 2, "✅ Broadcast sent to 2 subscribers."},
+		{EN, 1500, "✅ Broadcast sent to 1,500 subscribers."},
+		{TR, 1, "✅ Duyuru 1 aboneye gönderildi."},
+		{TR, 1500, "✅ Duyuru 1.500 aboneye gönderildi."},
+	}
+
+	for _, tc := range tests {
+		if got := N(tc.locale, AdminBroadcastSent, tc.n); got != tc.expected {
+			t.Errorf("N(%s, %d) = %q; want %q", tc.locale, tc.n, got, tc.expected)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected string
+		ok       bool
+	}{
+		{"tr", TR, true},
+		{"tr-TR", TR, true},
+		{"EN_us", EN, true},
+		{"de", "", false},
+		{"", "", false},
+	}
+
+	for _, tc := range tests {
+		got, ok := Match(tc.code, Supported())
+		if got != tc.expected || ok != tc.ok {
+			t.Errorf("Match(%q) = %q, %v; want %q, %v", tc.code, got, ok, tc.expected, tc.ok)
+		}
+	}
+}
+
+func TestTFallback(t *testing.T) {
+	if got := T("de", RegisterAlready); got != "You are already registered!" {
+		t.Errorf("T(de) = %q; want the English message", got)
+	}
+	if got := T(EN, "missing.key"); got != "missing.key" {
+		t.Errorf("T(missing) = %q; want the key", got)
+	}
+}
+
+func TestCatalogsComplete(t *testing.T) {
+	for locale, catalog := range catalogs {
+		for key := range catalogs[Default] {
+			if _, ok := catalog[key]; !ok {
+				t.Errorf("%s catalog is missing %q", locale, key)
+			}
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package i18n
+
+const (
+	UnknownCommand = "unknown_command"
+	ErrorGeneric   = "error_generic"
+	SlowDown       = "slow_down"
+
+	HelpRegister  = "help.register"
+	HelpDelete    = "help.delete"
+	HelpLanguage  = "help.language"
+	HelpSubscribe = "help.subscribe"
+	HelpRate      = "help.rate"
+	HelpHelp      = "help.help"
+	HelpHeader    = "help.header"
+	HelpStats     = "help.stats"
+	HelpBroadcast = "help.broadcast"
+	HelpFetchNow  = "help.fetchnow"
+	HelpUser      = "help.user"
+	HelpChannel   = "help.channel"
+	HelpLive      = "help.live"
+	HelpDigest    = "help.digest"
+	HelpPrices    = "help.prices"
+
+	UsageRate     = "usage.rate"
+	UsageLanguage = "usage.language"
+	UsageChannel  = "usage.channel"
+	UsageLive     = "usage.live"
+	UsageDigest   = "usage.digest"
+	UsagePrices   = "usage.prices"
+
+	RegisterSuccess = "register.success"
+	RegisterAlready = "register.already"
+	DeleteSuccess   = "delete.success"
+	DeleteMissing   = "delete.missing"
+	DeleteConfirm   = "delete.confirm"
+	DeleteCancelled = "delete.cancelled"
+
+	SubscribePrompt  = "subscribe.prompt"
+	SubscribeAdded   = "subscribe.added"
+	SubscribeRemoved = "subscribe.removed"
+
+	RateMessage         = "rate.message"
+	RateUnknownCurrency = "rate.unknown_currency"
+	RateRefreshed       = "rate.refreshed"
+
+	ButtonYes     = "button.yes"
+	ButtonNo      = "button.no"
+	ButtonRefresh = "button.refresh"
+
+	CallbackInvalid = "callback.invalid"
+
+	InlineTitle    = "inline.title"
+	InlinePrices   = "inline.prices"
+	InlineBulletin = "inline.bulletin"
+
+	LanguageCurrent     = "language.current"
+	LanguageChanged     = "language.changed"
+	LanguageUnsupported = "language.unsupported"
+
+	GroupAdminOnly = "group.admin_only"
+
+	ChannelNotFound    = "channel.not_found"
+	ChannelBotNotAdmin = "channel.bot_not_admin"
+	ChannelNotAdmin    = "channel.not_admin"
+	ChannelRegistered  = "channel.registered"
+	ChannelAlready     = "channel.already"
+	ChannelRemoved     = "channel.removed"
+	ChannelMissing     = "channel.missing"
+
+	LiveHeader        = "live.header"
+	LiveOff           = "live.off"
+	LiveNotEnabled    = "live.not_enabled"
+	LiveNotRegistered = "live.not_registered"
+
+	DigestDailyOn      = "digest.daily_on"
+	DigestWeeklyOn     = "digest.weekly_on"
+	DigestNone         = "digest.none"
+	DigestOff          = "digest.off"
+	DigestDailyHeader  = "digest.daily_header"
+	DigestWeeklyHeader = "digest.weekly_header"
+	DigestLine         = "digest.line"
+	DigestPercent      = "digest.percent"
+	DigestNoChange     = "digest.no_change"
+
+	PricesForex    = "prices.forex"
+	PricesBanknote = "prices.banknote"
+
+	AdminUnauthorized     = "admin.unauthorized"
+	AdminStats            = "admin.stats"
+	AdminBroadcastUsage   = "admin.broadcast_usage"
+	AdminBroadcastNone    = "admin.broadcast_none"
+	AdminBroadcastDiscard = "admin.broadcast_discard"
+	AdminBroadcastPreview = "admin.broadcast_preview"
+	AdminBroadcastSent    = "admin.broadcast_sent"
+	AdminFetchNow         = "admin.fetchnow"
+	AdminUserUsage        = "admin.user_usage"
+	AdminUserNotFound     = "admin.user_not_found"
+	AdminUserInfo         = "admin.user_info"
+	AdminUserActive       = "admin.user_active"
+	AdminUserBlockedSince = "admin.user_blocked_since"
+)
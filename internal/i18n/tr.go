@@ -0,0 +1,105 @@
+package i18n
+
+var tr = map[string]message{
+	UnknownCommand: {other: "Bilinmeyen komut. Neler yapabildiğimi görmek için /help yazın."},
+	ErrorGeneric:   {other: "Beklenmeyen bir hata oluştu. Lütfen daha sonra tekrar deneyin."},
+	SlowDown:       {other: "Biraz yavaşlayın! Çok hızlı komut gönderiyorsunuz; bir süre yok sayacağım."},
+
+	HelpRegister:  {other: "Saatlik EUR→TRY güncellemeleri için kaydol"},
+	HelpDelete:    {other: "Güncellemeleri almayı bırak"},
+	HelpLanguage:  {other: "Bot dilini değiştir"},
+	HelpSubscribe: {other: "Almak istediğiniz dövizleri seçin"},
+	HelpRate:      {other: "Güncel kurları göster, ör. /rate USD"},
+	HelpHelp:      {other: "Kullanılabilir komutları listele"},
+	HelpHeader:    {other: "Kullanılabilir komutlar:"},
+	HelpStats:     {other: "Abone ve gönderim istatistikleri"},
+	HelpBroadcast: {other: "Tüm abonelere mesaj gönder"},
+	HelpFetchNow:  {other: "Abonelere hemen bildirim gönder"},
+	HelpUser:      {other: "Bir aboneyi incele"},
+	HelpChannel:   {other: "Yönettiğiniz bir kanala güncelleme gönder"},
+	HelpLive:      {other: "Sabitlenmiş, canlı güncellenen tek bir kur mesajı tut"},
+	HelpDigest:    {other: "Günlük veya haftalık özet al"},
+	HelpPrices:    {other: "Döviz veya efektif kurları seç"},
+
+	UsageRate:     {other: "Kullanım: /rate [KOD ...], en fazla 5 kod, ör. /rate USD EUR"},
+	UsageLanguage: {other: "Kullanım: /language [en|tr]"},
+	UsageChannel:  {other: "Kullanım: /channel @kanal [delete]"},
+	UsageLive:     {other: "Kullanım: /live on|off"},
+	UsageDigest:   {other: "Kullanım: /digest [daily|weekly|off]"},
+	UsagePrices:   {other: "Kullanım: /prices [forex|banknote]"},
+
+	RegisterSuccess: {other: "✅ Kaydınız tamamlandı! Saatlik EUR→TRY güncellemeleri alacaksınız."},
+	RegisterAlready: {other: "Zaten kayıtlısınız!"},
+	DeleteSuccess:   {other: "🗑️ Kaydınız silindi. Artık güncelleme almayacaksınız."},
+	DeleteMissing:   {other: "Kayıtlı değilsiniz!"},
+	DeleteConfirm:   {other: "Kaydınızı silmek istediğinize emin misiniz? Artık güncelleme almayacaksınız."},
+	DeleteCancelled: {other: "Kayıt silme iptal edildi."},
+
+	SubscribePrompt:  {other: "Abone olmak veya aboneliği bırakmak için bir dövize dokunun. Seçim yapmazsanız EUR gönderilir."},
+	SubscribeAdded:   {other: "%s: abone olundu"},
+	SubscribeRemoved: {other: "%s: abonelik iptal edildi"},
+
+	RateMessage:         {other: "%s→TRY Satış: %s Alış: %s (%s itibarıyla)"},
+	RateUnknownCurrency: {other: "Bilinmeyen döviz %q."},
+	RateRefreshed:       {other: "Kurlar güncellendi"},
+
+	ButtonYes:     {other: "Evet, sil"},
+	ButtonNo:      {other: "Hayır"},
+	ButtonRefresh: {other: "🔄 Yenile"},
+
+	CallbackInvalid: {other: "Bu buton artık geçerli değil."},
+
+	InlineTitle:    {other: "%s %s = %s %s"},
+	InlinePrices:   {other: "%s alış %s, satış %s"},
+	InlineBulletin: {other: "%s tarihli kurlar"},
+
+	LanguageCurrent:     {other: "Geçerli dil: Türkçe (%s).\nDeğiştirmek için /language en veya /language tr kullanın."},
+	LanguageChanged:     {other: "✅ Dil Türkçe olarak ayarlandı."},
+	LanguageUnsupported: {other: "Desteklenmeyen dil %q. Kullanılabilir diller: %s."},
+
+	GroupAdminOnly: {other: "⛔ Bu sohbetin güncellemelerini yalnızca grup yöneticileri değiştirebilir."},
+
+	ChannelNotFound:    {other: "%s kanalı bulunamadı. Önce beni kanala yönetici olarak ekleyin."},
+	ChannelBotNotAdmin: {other: "%s kanalında mesaj gönderme yetkisi olan bir yönetici olmam gerekiyor."},
+	ChannelNotAdmin:    {other: "⛔ %s güncellemelerini yalnızca kanalın yöneticileri değiştirebilir."},
+	ChannelRegistered:  {other: "✅ %s kur güncellemelerini alacak."},
+	ChannelAlready:     {other: "%s zaten kayıtlı."},
+	ChannelRemoved:     {other: "🗑️ %s artık güncelleme almayacak."},
+	ChannelMissing:     {other: "%s kayıtlı değil."},
+
+	LiveHeader:        {other: "📌 Canlı kurlar"},
+	LiveOff:           {other: "Canlı mod kapatıldı. Planlı güncellemeler yeniden yeni mesaj olarak gelecek."},
+	LiveNotEnabled:    {other: "Canlı mod açık değil."},
+	LiveNotRegistered: {other: "Canlı modu kullanmak için önce /register ile kaydolun."},
+
+	DigestDailyOn:      {other: "🗞️ Her sabah bir özet alacaksınız."},
+	DigestWeeklyOn:     {other: "🗞️ Her pazartesi sabahı bir özet alacaksınız."},
+	DigestNone:         {other: "Özet almıyorsunuz. Almak için /digest daily veya /digest weekly yazın."},
+	DigestOff:          {other: "Özet kapatıldı."},
+	DigestDailyHeader:  {other: "🗞️ Günlük özet, %s kapanışı"},
+	DigestWeeklyHeader: {other: "🗞️ Haftalık özet, %s kapanışı"},
+	DigestLine:         {other: "%s %s\n  Gün %s · Hafta %s\n  7 gün %s – %s %s"},
+	DigestPercent:      {other: "%%%s"},
+	DigestNoChange:     {other: "yok"},
+
+	PricesForex:    {other: "💱 Havalelerde geçerli döviz kurlarını alıyorsunuz. Nakit kurları için /prices banknote yazın."},
+	PricesBanknote: {other: "💵 Nakitte geçerli efektif kurları alıyorsunuz. Havale kurları için /prices forex yazın."},
+
+	AdminUnauthorized:     {other: "⛔ Bu komutu kullanma yetkiniz yok."},
+	AdminStats:            {other: "📊 İstatistikler\nAboneler: %s\nAktif: %s\nEngelleyen: %s\nBugün gönderilen mesaj: %s"},
+	AdminBroadcastUsage:   {other: "Kullanım: /broadcast <metin>"},
+	AdminBroadcastNone:    {other: "Bekleyen duyuru yok."},
+	AdminBroadcastDiscard: {other: "Duyuru iptal edildi."},
+	AdminBroadcastPreview: {
+		other: "📣 Bu mesaj en fazla %s aboneye gönderilecek:\n\n%s\n\nGöndermek için /broadcast confirm, iptal etmek için /broadcast cancel yazın.",
+	},
+	AdminBroadcastSent: {
+		other: "✅ Duyuru %s aboneye gönderildi.",
+	},
+	AdminFetchNow:         {other: "🔄 Bildirim tetiklendi."},
+	AdminUserUsage:        {other: "Kullanım: /user <chat_id>"},
+	AdminUserNotFound:     {other: "chat_id=%d olan abone bulunamadı."},
+	AdminUserInfo:         {other: "👤 Kullanıcı %d\nID: %d\nKayıt: %s\nDurum: %s"},
+	AdminUserActive:       {other: "aktif"},
+	AdminUserBlockedSince: {other: "%s tarihinden beri engelli"},
+}
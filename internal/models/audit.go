@@ -0,0 +1,44 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	AuditRegister     = "register"
+	AuditDelete       = "delete"
+	AuditSubscribe    = "subscribe"
+	AuditUnsubscribe  = "unsubscribe"
+	AuditDeactivate   = "deactivate"
+	AuditAdminAction  = "admin_action"
+	AuditUnauthorized = "unauthorized"
+
+	ActorSystem = "system"
+	ActorCLI    = "cli"
+)
+
+type AuditEvent struct {
+	ID        int64           `json:"id"`
+	Type      string          `json:"type"`
+	Actor     string          `json:"actor"`
+	ChatID    int64           `json:"chat_id"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+type AuditFilter struct {
+	ChatID int64
+	Type   string
+	Since  time.Time
+	Limit  int
+}
+
+func UserActor(id int64) string {
+	return fmt.Sprintf("user:%d", id)
+}
+
+func AdminActor(id int64) string {
+	return fmt.Sprintf("admin:%d", id)
+}
@@ -0,0 +1,21 @@
+package models
+
+import (
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+)
+
+// HistoricalRate is the last rate recorded for a currency on a day.
+type HistoricalRate struct {
+	Code    currency.Code
+	Date    time.Time
+	Unit    int
+	Buying  decimal.Decimal
+	Selling decimal.Decimal
+	// BanknoteBuying and BanknoteSelling are zero when the source quotes no
+	// banknote prices for the currency.
+	BanknoteBuying  decimal.Decimal
+	BanknoteSelling decimal.Decimal
+}
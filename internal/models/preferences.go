@@ -0,0 +1,13 @@
+package models
+
+type Preferences struct {
+	ChatID   int64
+	Language string
+	// LiveMessageID is the pinned message kept up to date in live mode, or 0
+	// when live mode is off.
+	LiveMessageID int
+	// Digest is "daily", "weekly" or "" when the chat gets no digest.
+	Digest string
+	// PriceType is "forex", "banknote" or "" for the default forex prices.
+	PriceType string
+}
@@ -0,0 +1,8 @@
+package models
+
+type Stats struct {
+	Subscribers int `json:"subscribers"`
+	Active      int `json:"active"`
+	Blocked     int `json:"blocked"`
+	SentToday   int `json:"sent_today"`
+}
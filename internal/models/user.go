@@ -1,6 +1,10 @@
 package models
 
+import "time"
+
 type User struct {
-	ID     int64 `json:"id"`
-	ChatID int64 `json:"chat_id"`
+	ID        int64      `json:"id"`
+	ChatID    int64      `json:"chat_id"`
+	CreatedAt time.Time  `json:"created_at"`
+	BlockedAt *time.Time `json:"blocked_at,omitempty"`
 }
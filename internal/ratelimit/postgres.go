@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Postgres keeps buckets in the rate_limits table so replicas share them.
+type Postgres struct {
+	db  *sql.DB
+	cfg Config
+	now func() time.Time
+}
+
+func NewPostgres(db *sql.DB, cfg Config) *Postgres {
+	return &Postgres{db: db, cfg: cfg, now: time.Now}
+}
+
+func (p *Postgres) Take(chatID int64) (Decision, error) {
+	selectQuery := `
+	SELECT tokens, warned, updated_at FROM rate_limits WHERE chat_id = $1 FOR UPDATE
+	`
+	upsertQuery := `
+	INSERT INTO rate_limits (chat_id, tokens, warned, updated_at) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (chat_id) DO UPDATE SET tokens = EXCLUDED.tokens, warned = EXCLUDED.warned, updated_at = EXCLUDED.updated_at
+	`
+
+	tx, err := p.db.Begin()
+	if err != nil {
+		return Allow, fmt.Errorf("Take begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	var b Bucket
+	err = tx.QueryRow(selectQuery, chatID).Scan(&b.Tokens, &b.Warned, &b.UpdatedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return Allow, fmt.Errorf("Take select: %w", err)
+	}
+
+	decision := p.cfg.Take(&b, p.now())
+
+	if _, err := tx.Exec(upsertQuery, chatID, b.Tokens, b.Warned, b.UpdatedAt); err != nil {
+		return Allow, fmt.Errorf("Take upsert: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return Allow, fmt.Errorf("Take commit: %w", err)
+	}
+
+	return decision, nil
+}
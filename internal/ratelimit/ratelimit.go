@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type Decision int
+
+const (
+	// Allow means the command may run.
+	Allow Decision = iota
+	// Warn is the first rejection after the bucket ran dry; the caller
+	// should tell the user once.
+	Warn
+	// Drop is any further rejection; the caller should stay silent.
+	Drop
+)
+
+type Limiter interface {
+	Take(chatID int64) (Decision, error)
+}
+
+// Config describes a token bucket holding up to Burst tokens and gaining one
+// every Refill.
+type Config struct {
+	Burst  int
+	Refill time.Duration
+}
+
+type Bucket struct {
+	Tokens    float64
+	Warned    bool
+	UpdatedAt time.Time
+}
+
+// Take refills b for the time passed since it was last updated and spends
+// one token if there is one.
+func (c Config) Take(b *Bucket, now time.Time) Decision {
+	if b.UpdatedAt.IsZero() {
+		b.Tokens = float64(c.Burst)
+	} else if elapsed := now.Sub(b.UpdatedAt); elapsed > 0 {
+		b.Tokens = min(float64(c.Burst), b.Tokens+float64(elapsed)/float64(c.Refill))
+	}
+	b.UpdatedAt = now
+
+	if b.Tokens >= 1 {
+		b.Tokens--
+		b.Warned = false
+		return Allow
+	}
+	if !b.Warned {
+		b.Warned = true
+		return Warn
+	}
+	return Drop
+}
+
+// full reports whether b would be back at Burst by now, so it can be
+// forgotten without changing any decision.
+func (c Config) full(b *Bucket, now time.Time) bool {
+	return b.Tokens+float64(now.Sub(b.UpdatedAt))/float64(c.Refill) >= float64(c.Burst)
+}
+
+const sweepEvery = 1000
+
+// Memory keeps buckets in process memory; each replica limits on its own.
+type Memory struct {
+	mu      sync.Mutex
+	cfg     Config
+	buckets map[int64]*Bucket
+	takes   int
+	now     func() time.Time
+}
+
+func NewMemory(cfg Config) *Memory {
+	return &Memory{cfg: cfg, buckets: make(map[int64]*Bucket), now: time.Now}
+}
+
+func (m *Memory) Take(chatID int64) (Decision, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+
+	m.takes++
+	if m.takes%sweepEvery == 0 {
+		for id, b := range m.buckets {
+			if m.cfg.full(b, now) {
+				delete(m.buckets, id)
+			}
+		}
+	}
+
+	b, ok := m.buckets[chatID]
+	if !ok {
+		b = &Bucket{}
+		m.buckets[chatID] = b
+	}
+	return m.cfg.Take(b, now), nil
+}
@@ -0,0 +1,156 @@
+package ratelimit
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var start = time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+
+func TestMemory(t *testing.T) {
+	m := NewMemory(Config{Burst: 3, Refill: time.Second})
+	now := start
+	m.now = func() time.Time { return now }
+
+	take := func() Decision {
+		d, err := m.Take(1)
+		if err != nil {
+			t.Fatalf("Take: %v", err)
+		}
+		return d
+	}
+
+	var got []Decision
+	for i := 0; i < 6; i++ {
+		got = append(got, take())
+	}
+	want := []Decision{Allow, Allow, Allow, Warn, Drop, Drop}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("decisions = %v; want %v", got, want)
+		}
+	}
+
+	if d, _ := m.Take(2); d != Allow {
+		t.Errorf("other chat = %v; want Allow", d)
+	}
+
+	now = now.Add(1500 * time.Millisecond)
+	if d := take(); d != Allow {
+		t.Errorf("after refill = %v; want Allow", d)
+	}
+	if d := take(); d != Warn {
+		t.Errorf("after spending the refill = %v; want Warn again", d)
+	}
+
+	now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if d := take(); d != Allow {
+			t.Fatalf("refill is capped at burst; take %d = %v", i, d)
+		}
+	}
+	if d := take(); d != Warn {
+		t.Errorf("fourth take after a long pause = %v; want Warn", d)
+	}
+}
+
+func TestMemorySweep(t *testing.T) {
+	m := NewMemory(Config{Burst: 1, Refill: time.Second})
+	now := start
+	m.now = func() time.Time { return now }
+
+	for i := 0; i < sweepEvery-1; i++ {
+		m.Take(int64(i))
+	}
+	now = now.Add(time.Minute)
+	m.Take(-1)
+
+	if len(m.buckets) != 1 {
+		t.Errorf("buckets after sweep = %d; want 1", len(m.buckets))
+	}
+}
+
+const (
+	selectBucketQuery = "SELECT tokens, warned, updated_at FROM rate_limits WHERE chat_id = $1 FOR UPDATE"
+	upsertBucketQuery = "INSERT INTO rate_limits (chat_id, tokens, warned, updated_at) VALUES ($1, $2, $3, $4)"
+)
+
+func TestPostgres(t *testing.T) {
+	tests := []struct {
+		name                string
+		mockSetup           func(mock sqlmock.Sqlmock)
+		expected            Decision
+		expectedErrorString string
+	}{
+		{
+			name: "NewBucket",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(selectBucketQuery)).WithArgs(7).WillReturnError(sql.ErrNoRows)
+				mock.ExpectExec(regexp.QuoteMeta(upsertBucketQuery)).
+					WithArgs(7, 1.0, false, start).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expected: Allow,
+		},
+		{
+			name: "Empty",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"tokens", "warned", "updated_at"}).AddRow(0.0, false, start)
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(selectBucketQuery)).WithArgs(7).WillReturnRows(rows)
+				mock.ExpectExec(regexp.QuoteMeta(upsertBucketQuery)).
+					WithArgs(7, 0.0, true, start).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+			expected: Warn,
+		},
+		{
+			name: "SelectError",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectQuery(regexp.QuoteMeta(selectBucketQuery)).WillReturnError(errors.New("ERROR"))
+				mock.ExpectRollback()
+			},
+			expected:            Allow,
+			expectedErrorString: "Take select:",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbMock, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock :%v", err)
+			}
+			defer dbMock.Close()
+
+			tc.mockSetup(mock)
+
+			p := NewPostgres(dbMock, Config{Burst: 2, Refill: time.Second})
+			p.now = func() time.Time { return start }
+
+			d, err := p.Take(7)
+
+			if tc.expectedErrorString == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got :%v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.expectedErrorString) {
+				t.Errorf("error = %v; want it to contain %s", err, tc.expectedErrorString)
+			}
+			if d != tc.expected {
+				t.Errorf("decision = %v; want %v", d, tc.expected)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
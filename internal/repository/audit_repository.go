@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/akyTheDev/currency-bot/internal/models"
+)
+
+const defaultAuditLimit = 100
+
+type PostgresAuditRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresAuditRepository(db *sql.DB) *PostgresAuditRepository {
+	return &PostgresAuditRepository{db: db}
+}
+
+type AuditRepository interface {
+	Record(event models.AuditEvent) error
+	ListAuditEvents(filter models.AuditFilter) ([]models.AuditEvent, error)
+}
+
+type execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+func insertAuditEvent(ex execer, event models.AuditEvent) error {
+	query := `
+	INSERT INTO audit_events (type, actor, chat_id, payload) VALUES ($1, $2, $3, $4::jsonb)
+	`
+
+	payload := "{}"
+	if len(event.Payload) > 0 {
+		payload = string(event.Payload)
+	}
+
+	_, err := ex.Exec(query, event.Type, event.Actor, event.ChatID, payload)
+	return err
+}
+
+func (ar *PostgresAuditRepository) Record(event models.AuditEvent) error {
+	if err := insertAuditEvent(ar.db, event); err != nil {
+		return fmt.Errorf("Record exec: %w", err)
+	}
+	return nil
+}
+
+func (ar *PostgresAuditRepository) ListAuditEvents(filter models.AuditFilter) ([]models.AuditEvent, error) {
+	var conditions []string
+	var args []any
+
+	if filter.ChatID != 0 {
+		args = append(args, filter.ChatID)
+		conditions = append(conditions, fmt.Sprintf("chat_id = $%d", len(args)))
+	}
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		conditions = append(conditions, fmt.Sprintf("type = $%d", len(args)))
+	}
+	if !filter.Since.IsZero() {
+		args = append(args, filter.Since)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultAuditLimit
+	}
+	args = append(args, limit)
+
+	query := "SELECT id, type, actor, chat_id, payload, created_at FROM audit_events"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := ar.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListAuditEvents query: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.AuditEvent
+	for rows.Next() {
+		var event models.AuditEvent
+		var payload []byte
+		err = rows.Scan(
+			&event.ID,
+			&event.Type,
+			&event.Actor,
+			&event.ChatID,
+			&payload,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("ListAuditEvents scan: %w", err)
+		}
+		event.Payload = payload
+		events = append(events, event)
+	}
+
+	return events, nil
+}
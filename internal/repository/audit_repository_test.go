@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/akyTheDev/currency-bot/internal/models"
+)
+
+func TestPostgresAuditRepository_Record(t *testing.T) {
+	dbMock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock :%v", err)
+	}
+	defer dbMock.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta(auditInsertQuery)).
+		WithArgs("admin_action", "admin:1", 1, `{"command":"stats"}`).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta(auditInsertQuery)).WillReturnError(errors.New("ERROR"))
+
+	repo := NewPostgresAuditRepository(dbMock)
+	event := models.AuditEvent{
+		Type:    models.AuditAdminAction,
+		Actor:   models.AdminActor(1),
+		ChatID:  1,
+		Payload: []byte(`{"command":"stats"}`),
+	}
+
+	if err := repo.Record(event); err != nil {
+		t.Errorf("Expected no error, got :%v", err)
+	}
+	if err := repo.Record(event); err == nil || !strings.Contains(err.Error(), "Record exec:") {
+		t.Errorf("error = %v; want it to contain Record exec:", err)
+	}
+}
+
+func TestPostgresAuditRepository_ListAuditEvents(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	columns := []string{"id", "type", "actor", "chat_id", "payload", "created_at"}
+
+	tests := []struct {
+		name                string
+		filter              models.AuditFilter
+		mockSetup           func(mock sqlmock.Sqlmock)
+		expectedLen         int
+		expectedErrorString string
+	}{
+		{
+			name:   "NoFilter",
+			filter: models.AuditFilter{},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).
+					AddRow(2, "delete", "user:5", 5, []byte("{}"), createdAt).
+					AddRow(1, "register", "user:5", 5, []byte("{}"), createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"SELECT id, type, actor, chat_id, payload, created_at FROM audit_events ORDER BY created_at DESC, id DESC LIMIT $1",
+				)).WithArgs(100).WillReturnRows(rows)
+			},
+			expectedLen: 2,
+		},
+		{
+			name:   "AllFilters",
+			filter: models.AuditFilter{ChatID: 5, Type: "register", Since: createdAt, Limit: 10},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).AddRow(1, "register", "user:5", 5, []byte("{}"), createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta(
+					"FROM audit_events WHERE chat_id = $1 AND type = $2 AND created_at >= $3 ORDER BY created_at DESC, id DESC LIMIT $4",
+				)).WithArgs(5, "register", createdAt, 10).WillReturnRows(rows)
+			},
+			expectedLen: 1,
+		},
+		{
+			name:   "QueryError",
+			filter: models.AuditFilter{Type: "delete"},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM audit_events WHERE type = $1")).WillReturnError(errors.New("ERROR"))
+			},
+			expectedErrorString: "ListAuditEvents query:",
+		},
+		{
+			name:   "ScanError",
+			filter: models.AuditFilter{},
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(columns).AddRow("not_integer", "register", "user:5", 5, []byte("{}"), createdAt)
+				mock.ExpectQuery(regexp.QuoteMeta("FROM audit_events")).WillReturnRows(rows)
+			},
+			expectedErrorString: "ListAuditEvents scan:",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbMock, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock :%v", err)
+			}
+			defer dbMock.Close()
+
+			tc.mockSetup(mock)
+
+			events, err := NewPostgresAuditRepository(dbMock).ListAuditEvents(tc.filter)
+
+			if tc.expectedErrorString == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got :%v", err)
+				}
+				if len(events) != tc.expectedLen {
+					t.Errorf("got %d events; want %d", len(events), tc.expectedLen)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.expectedErrorString) {
+				t.Errorf("error = %v; want it to contain %s", err, tc.expectedErrorString)
+			}
+		})
+	}
+}
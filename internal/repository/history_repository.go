@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/models"
+)
+
+type PostgresHistoryRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresHistoryRepository(db *sql.DB) *PostgresHistoryRepository {
+	return &PostgresHistoryRepository{db: db}
+}
+
+type HistoryRepository interface {
+	Record(rates []models.HistoricalRate) error
+	GetHistory(from, to time.Time) ([]models.HistoricalRate, error)
+}
+
+// Record stores rates, replacing the ones already recorded for the same
+// currency and day so the last rate of a day is its close.
+func (hr *PostgresHistoryRepository) Record(rates []models.HistoricalRate) error {
+	query := `
+	INSERT INTO rate_history (currency, date, unit, buying, selling, banknote_buying, banknote_selling) VALUES ($1, $2, $3, $4, $5, $6, $7)
+	ON CONFLICT (currency, date) DO UPDATE SET unit = EXCLUDED.unit, buying = EXCLUDED.buying, selling = EXCLUDED.selling,
+		banknote_buying = EXCLUDED.banknote_buying, banknote_selling = EXCLUDED.banknote_selling, recorded_at = CURRENT_TIMESTAMP
+	`
+
+	tx, err := hr.db.Begin()
+	if err != nil {
+		return fmt.Errorf("Record begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, r := range rates {
+		if _, err := tx.Exec(query, r.Code, r.Date, r.Unit, r.Buying, r.Selling, r.BanknoteBuying, r.BanknoteSelling); err != nil {
+			return fmt.Errorf("Record exec: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("Record commit: %w", err)
+	}
+	return nil
+}
+
+// GetHistory returns the rates recorded between from and to, inclusive,
+// ordered by currency and date.
+func (hr *PostgresHistoryRepository) GetHistory(from, to time.Time) ([]models.HistoricalRate, error) {
+	query := `
+	SELECT currency, date, unit, buying, selling, banknote_buying, banknote_selling FROM rate_history
+	WHERE date BETWEEN $1 AND $2
+	ORDER BY currency, date
+	`
+
+	rows, err := hr.db.Query(query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("GetHistory query: %w", err)
+	}
+	defer rows.Close()
+
+	var history []models.HistoricalRate
+	for rows.Next() {
+		var r models.HistoricalRate
+		if err := rows.Scan(&r.Code, &r.Date, &r.Unit, &r.Buying, &r.Selling, &r.BanknoteBuying, &r.BanknoteSelling); err != nil {
+			return nil, fmt.Errorf("GetHistory scan: %w", err)
+		}
+		history = append(history, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetHistory rows: %w", err)
+	}
+
+	return history, nil
+}
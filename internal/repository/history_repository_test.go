@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+	"github.com/akyTheDev/currency-bot/internal/models"
+)
+
+func TestPostgresHistoryRepository_Record(t *testing.T) {
+	day := time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)
+	rates := []models.HistoricalRate{
+		{Code: "USD", Date: day, Unit: 1, Buying: decimal.MustParse("35.1"), Selling: decimal.MustParse("35.2"),
+			BanknoteBuying: decimal.MustParse("35.05"), BanknoteSelling: decimal.MustParse("35.3")},
+		{Code: "JPY", Date: day, Unit: 100, Buying: decimal.MustParse("22.3"), Selling: decimal.MustParse("22.5")},
+	}
+	insertQuery := "INSERT INTO rate_history (currency, date, unit, buying, selling, banknote_buying, banknote_selling) VALUES ($1, $2, $3, $4, $5, $6, $7)"
+
+	tests := []struct {
+		name                string
+		mockSetup           func(mock sqlmock.Sqlmock)
+		expectedErrorString string
+	}{
+		{
+			name: "Success",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(insertQuery)).WithArgs("USD", day, 1, "35.1", "35.2", "35.05", "35.3").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(insertQuery)).WithArgs("JPY", day, 100, "22.3", "22.5", "0", "0").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "ExecError",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(insertQuery)).WillReturnError(errors.New("ERROR"))
+				mock.ExpectRollback()
+			},
+			expectedErrorString: "Record exec:",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbMock, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock :%v", err)
+			}
+			defer dbMock.Close()
+
+			tc.mockSetup(mock)
+
+			err = NewPostgresHistoryRepository(dbMock).Record(rates)
+
+			if tc.expectedErrorString == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got :%v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.expectedErrorString) {
+				t.Errorf("error = %v; want it to contain %s", err, tc.expectedErrorString)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresHistoryRepository_GetHistory(t *testing.T) {
+	dbMock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock :%v", err)
+	}
+	defer dbMock.Close()
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	rows := sqlmock.NewRows([]string{"currency", "date", "unit", "buying", "selling", "banknote_buying", "banknote_selling"}).
+		AddRow("USD", from, 1, "35.000000", "35.100000", "34.900000", "35.200000").
+		AddRow("USD", to, 1, "35.200000", "35.300000", "35.100000", "35.400000")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, date, unit, buying, selling, banknote_buying, banknote_selling FROM rate_history")).
+		WithArgs(from, to).WillReturnRows(rows)
+
+	history, err := NewPostgresHistoryRepository(dbMock).GetHistory(from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got :%v", err)
+	}
+	if len(history) != 2 || !history[1].Selling.Equal(decimal.MustParse("35.3")) || !history[1].BanknoteSelling.Equal(decimal.MustParse("35.4")) || !history[1].Date.Equal(to) {
+		t.Errorf("history = %+v", history)
+	}
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/models"
+)
+
+type PostgresPreferenceRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresPreferenceRepository(db *sql.DB) *PostgresPreferenceRepository {
+	return &PostgresPreferenceRepository{db: db}
+}
+
+type PreferenceRepository interface {
+	GetPreferences(chatID int64) (*models.Preferences, error)
+	GetLanguages() (map[int64]string, error)
+	SetLanguage(chatID int64, language string) error
+	InitLanguage(chatID int64, language string) error
+	GetLiveMessages() (map[int64]int, error)
+	SetLiveMessage(chatID int64, messageID int) error
+	SetDigest(chatID int64, digest string) error
+	GetPendingDigests(day time.Time) (map[int64]string, error)
+	MarkDigestSent(chatID int64, day time.Time) error
+	GetPriceTypes() (map[int64]string, error)
+	SetPriceType(chatID int64, priceType string) error
+}
+
+// GetPreferences returns the stored preferences of a chat, or empty
+// preferences when nothing has been stored yet.
+func (pr *PostgresPreferenceRepository) GetPreferences(chatID int64) (*models.Preferences, error) {
+	query := `
+	SELECT COALESCE(language, ''), COALESCE(live_message_id, 0), COALESCE(digest, ''), COALESCE(price_type, '')
+	FROM chat_preferences WHERE chat_id = $1
+	`
+
+	prefs := &models.Preferences{ChatID: chatID}
+	err := pr.db.QueryRow(query, chatID).Scan(&prefs.Language, &prefs.LiveMessageID, &prefs.Digest, &prefs.PriceType)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("GetPreferences query: %w", err)
+	}
+
+	return prefs, nil
+}
+
+func (pr *PostgresPreferenceRepository) GetLanguages() (map[int64]string, error) {
+	query := `
+	SELECT chat_id, language FROM chat_preferences WHERE language IS NOT NULL
+	`
+
+	rows, err := pr.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("GetLanguages query: %w", err)
+	}
+	defer rows.Close()
+
+	languages := make(map[int64]string)
+	for rows.Next() {
+		var chatID int64
+		var language string
+		if err := rows.Scan(&chatID, &language); err != nil {
+			return nil, fmt.Errorf("GetLanguages scan: %w", err)
+		}
+		languages[chatID] = language
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetLanguages rows: %w", err)
+	}
+
+	return languages, nil
+}
+
+func (pr *PostgresPreferenceRepository) SetLanguage(chatID int64, language string) error {
+	query := `
+	INSERT INTO chat_preferences (chat_id, language) VALUES ($1, $2)
+	ON CONFLICT (chat_id) DO UPDATE SET language = EXCLUDED.language, updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := pr.db.Exec(query, chatID, language); err != nil {
+		return fmt.Errorf("SetLanguage exec: %w", err)
+	}
+	return nil
+}
+
+// InitLanguage stores language only if the chat has not chosen one yet.
+func (pr *PostgresPreferenceRepository) InitLanguage(chatID int64, language string) error {
+	query := `
+	INSERT INTO chat_preferences (chat_id, language) VALUES ($1, $2)
+	ON CONFLICT (chat_id) DO UPDATE SET language = EXCLUDED.language, updated_at = CURRENT_TIMESTAMP
+	WHERE chat_preferences.language IS NULL
+	`
+
+	if _, err := pr.db.Exec(query, chatID, language); err != nil {
+		return fmt.Errorf("InitLanguage exec: %w", err)
+	}
+	return nil
+}
+
+// GetLiveMessages returns the live message of every chat in live mode.
+func (pr *PostgresPreferenceRepository) GetLiveMessages() (map[int64]int, error) {
+	query := `
+	SELECT chat_id, live_message_id FROM chat_preferences WHERE live_message_id IS NOT NULL
+	`
+
+	rows, err := pr.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("GetLiveMessages query: %w", err)
+	}
+	defer rows.Close()
+
+	messages := make(map[int64]int)
+	for rows.Next() {
+		var chatID int64
+		var messageID int
+		if err := rows.Scan(&chatID, &messageID); err != nil {
+			return nil, fmt.Errorf("GetLiveMessages scan: %w", err)
+		}
+		messages[chatID] = messageID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetLiveMessages rows: %w", err)
+	}
+
+	return messages, nil
+}
+
+// SetLiveMessage stores the chat's live message; a messageID of 0 turns
+// live mode off.
+func (pr *PostgresPreferenceRepository) SetLiveMessage(chatID int64, messageID int) error {
+	query := `
+	INSERT INTO chat_preferences (chat_id, live_message_id) VALUES ($1, $2)
+	ON CONFLICT (chat_id) DO UPDATE SET live_message_id = EXCLUDED.live_message_id, updated_at = CURRENT_TIMESTAMP
+	`
+
+	var value sql.NullInt64
+	if messageID != 0 {
+		value = sql.NullInt64{Int64: int64(messageID), Valid: true}
+	}
+
+	if _, err := pr.db.Exec(query, chatID, value); err != nil {
+		return fmt.Errorf("SetLiveMessage exec: %w", err)
+	}
+	return nil
+}
+
+// SetDigest stores the chat's digest kind; an empty digest turns it off.
+func (pr *PostgresPreferenceRepository) SetDigest(chatID int64, digest string) error {
+	query := `
+	INSERT INTO chat_preferences (chat_id, digest) VALUES ($1, $2)
+	ON CONFLICT (chat_id) DO UPDATE SET digest = EXCLUDED.digest, updated_at = CURRENT_TIMESTAMP
+	`
+
+	value := sql.NullString{String: digest, Valid: digest != ""}
+	if _, err := pr.db.Exec(query, chatID, value); err != nil {
+		return fmt.Errorf("SetDigest exec: %w", err)
+	}
+	return nil
+}
+
+// GetPendingDigests returns the digest kind of every chat that wants one and
+// has not been sent one on day yet.
+func (pr *PostgresPreferenceRepository) GetPendingDigests(day time.Time) (map[int64]string, error) {
+	query := `
+	SELECT chat_id, digest FROM chat_preferences
+	WHERE digest IS NOT NULL AND (digest_sent_on IS NULL OR digest_sent_on < $1)
+	`
+
+	rows, err := pr.db.Query(query, day)
+	if err != nil {
+		return nil, fmt.Errorf("GetPendingDigests query: %w", err)
+	}
+	defer rows.Close()
+
+	digests := make(map[int64]string)
+	for rows.Next() {
+		var chatID int64
+		var digest string
+		if err := rows.Scan(&chatID, &digest); err != nil {
+			return nil, fmt.Errorf("GetPendingDigests scan: %w", err)
+		}
+		digests[chatID] = digest
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetPendingDigests rows: %w", err)
+	}
+
+	return digests, nil
+}
+
+func (pr *PostgresPreferenceRepository) MarkDigestSent(chatID int64, day time.Time) error {
+	query := `
+	UPDATE chat_preferences SET digest_sent_on = $2 WHERE chat_id = $1
+	`
+
+	if _, err := pr.db.Exec(query, chatID, day); err != nil {
+		return fmt.Errorf("MarkDigestSent exec: %w", err)
+	}
+	return nil
+}
+
+// GetPriceTypes returns the price type of every chat that chose one.
+func (pr *PostgresPreferenceRepository) GetPriceTypes() (map[int64]string, error) {
+	query := `
+	SELECT chat_id, price_type FROM chat_preferences WHERE price_type IS NOT NULL
+	`
+
+	rows, err := pr.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("GetPriceTypes query: %w", err)
+	}
+	defer rows.Close()
+
+	priceTypes := make(map[int64]string)
+	for rows.Next() {
+		var chatID int64
+		var priceType string
+		if err := rows.Scan(&chatID, &priceType); err != nil {
+			return nil, fmt.Errorf("GetPriceTypes scan: %w", err)
+		}
+		priceTypes[chatID] = priceType
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetPriceTypes rows: %w", err)
+	}
+
+	return priceTypes, nil
+}
+
+func (pr *PostgresPreferenceRepository) SetPriceType(chatID int64, priceType string) error {
+	query := `
+	INSERT INTO chat_preferences (chat_id, price_type) VALUES ($1, $2)
+	ON CONFLICT (chat_id) DO UPDATE SET price_type = EXCLUDED.price_type, updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := pr.db.Exec(query, chatID, priceType); err != nil {
+		return fmt.Errorf("SetPriceType exec: %w", err)
+	}
+	return nil
+}
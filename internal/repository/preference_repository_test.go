@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPostgresPreferenceRepository_GetPreferences(t *testing.T) {
+	tests := []struct {
+		name                string
+		mockSetup           func(mock sqlmock.Sqlmock)
+		expectedLanguage    string
+		expectedErrorString string
+	}{
+		{
+			name: "Stored",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"language", "live_message_id", "digest", "price_type"}).AddRow("tr", 0, "", "banknote")
+				mock.ExpectQuery(regexp.QuoteMeta("COALESCE(digest, ''), COALESCE(price_type, '')\n\tFROM chat_preferences WHERE chat_id = $1")).
+					WithArgs(12345).WillReturnRows(rows)
+			},
+			expectedLanguage: "tr",
+		},
+		{
+			name: "NotStored",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM chat_preferences")).WillReturnError(sql.ErrNoRows)
+			},
+			expectedLanguage: "",
+		},
+		{
+			name: "QueryError",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM chat_preferences")).WillReturnError(errors.New("ERROR"))
+			},
+			expectedErrorString: "GetPreferences query:",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbMock, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock :%v", err)
+			}
+			defer dbMock.Close()
+
+			tc.mockSetup(mock)
+
+			prefs, err := NewPostgresPreferenceRepository(dbMock).GetPreferences(12345)
+
+			if tc.expectedErrorString != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErrorString) {
+					t.Errorf("error = %v; want it to contain %s", err, tc.expectedErrorString)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got :%v", err)
+			}
+			if prefs.ChatID != 12345 || prefs.Language != tc.expectedLanguage {
+				t.Errorf("prefs = %+v; want language %q", prefs, tc.expectedLanguage)
+			}
+		})
+	}
+}
+
+func TestPostgresPreferenceRepository_GetLanguages(t *testing.T) {
+	dbMock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock :%v", err)
+	}
+	defer dbMock.Close()
+
+	rows := sqlmock.NewRows([]string{"chat_id", "language"}).AddRow(1, "tr").AddRow(2, "en")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT chat_id, language FROM chat_preferences WHERE language IS NOT NULL")).
+		WillReturnRows(rows)
+
+	languages, err := NewPostgresPreferenceRepository(dbMock).GetLanguages()
+	if err != nil {
+		t.Fatalf("Expected no error, got :%v", err)
+	}
+	if len(languages) != 2 || languages[1] != "tr" || languages[2] != "en" {
+		t.Errorf("languages = %v; want map[1:tr 2:en]", languages)
+	}
+}
+
+func TestPostgresPreferenceRepository_SetLanguage(t *testing.T) {
+	tests := []struct {
+		name                string
+		init                bool
+		query               string
+		execErr             error
+		expectedErrorString string
+	}{
+		{name: "Set", query: "ON CONFLICT (chat_id) DO UPDATE SET language = EXCLUDED.language"},
+		{name: "SetError", execErr: errors.New("ERROR"), expectedErrorString: "SetLanguage exec:"},
+		{name: "Init", init: true, query: "WHERE chat_preferences.language IS NULL"},
+		{name: "InitError", init: true, execErr: errors.New("ERROR"), expectedErrorString: "InitLanguage exec:"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbMock, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock :%v", err)
+			}
+			defer dbMock.Close()
+
+			expect := mock.ExpectExec(regexp.QuoteMeta(tc.query)).WithArgs(12345, "tr")
+			if tc.execErr != nil {
+				expect.WillReturnError(tc.execErr)
+			} else {
+				expect.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			repo := NewPostgresPreferenceRepository(dbMock)
+			if tc.init {
+				err = repo.InitLanguage(12345, "tr")
+			} else {
+				err = repo.SetLanguage(12345, "tr")
+			}
+
+			if tc.expectedErrorString == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got :%v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.expectedErrorString) {
+				t.Errorf("error = %v; want it to contain %s", err, tc.expectedErrorString)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestPostgresPreferenceRepository_LiveMessages(t *testing.T) {
+	dbMock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock :%v", err)
+	}
+	defer dbMock.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("ON CONFLICT (chat_id) DO UPDATE SET live_message_id = EXCLUDED.live_message_id")).
+		WithArgs(12345, sql.NullInt64{Int64: 77, Valid: true}).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("live_message_id = EXCLUDED.live_message_id")).
+		WithArgs(12345, sql.NullInt64{}).WillReturnResult(sqlmock.NewResult(0, 1))
+	rows := sqlmock.NewRows([]string{"chat_id", "live_message_id"}).AddRow(1, 77)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT chat_id, live_message_id FROM chat_preferences WHERE live_message_id IS NOT NULL")).
+		WillReturnRows(rows)
+
+	repo := NewPostgresPreferenceRepository(dbMock)
+	if err := repo.SetLiveMessage(12345, 77); err != nil {
+		t.Errorf("SetLiveMessage: %v", err)
+	}
+	if err := repo.SetLiveMessage(12345, 0); err != nil {
+		t.Errorf("SetLiveMessage(0): %v", err)
+	}
+	messages, err := repo.GetLiveMessages()
+	if err != nil || len(messages) != 1 || messages[1] != 77 {
+		t.Errorf("GetLiveMessages = %v, %v; want map[1:77]", messages, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresPreferenceRepository_Digests(t *testing.T) {
+	dbMock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock :%v", err)
+	}
+	defer dbMock.Close()
+
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta("ON CONFLICT (chat_id) DO UPDATE SET digest = EXCLUDED.digest")).
+		WithArgs(12345, sql.NullString{String: "daily", Valid: true}).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("digest = EXCLUDED.digest")).
+		WithArgs(12345, sql.NullString{}).WillReturnResult(sqlmock.NewResult(0, 1))
+	rows := sqlmock.NewRows([]string{"chat_id", "digest"}).AddRow(1, "weekly")
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE digest IS NOT NULL AND (digest_sent_on IS NULL OR digest_sent_on < $1)")).
+		WithArgs(day).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE chat_preferences SET digest_sent_on = $2 WHERE chat_id = $1")).
+		WithArgs(1, day).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	repo := NewPostgresPreferenceRepository(dbMock)
+	if err := repo.SetDigest(12345, "daily"); err != nil {
+		t.Errorf("SetDigest: %v", err)
+	}
+	if err := repo.SetDigest(12345, ""); err != nil {
+		t.Errorf("SetDigest(off): %v", err)
+	}
+	digests, err := repo.GetPendingDigests(day)
+	if err != nil || len(digests) != 1 || digests[1] != "weekly" {
+		t.Errorf("GetPendingDigests = %v, %v; want map[1:weekly]", digests, err)
+	}
+	if err := repo.MarkDigestSent(1, day); err != nil {
+		t.Errorf("MarkDigestSent: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+func TestPostgresPreferenceRepository_PriceTypes(t *testing.T) {
+	dbMock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock :%v", err)
+	}
+	defer dbMock.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("ON CONFLICT (chat_id) DO UPDATE SET price_type = EXCLUDED.price_type")).
+		WithArgs(12345, "banknote").WillReturnResult(sqlmock.NewResult(0, 1))
+	rows := sqlmock.NewRows([]string{"chat_id", "price_type"}).AddRow(12345, "banknote")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT chat_id, price_type FROM chat_preferences WHERE price_type IS NOT NULL")).
+		WillReturnRows(rows)
+
+	repo := NewPostgresPreferenceRepository(dbMock)
+	if err := repo.SetPriceType(12345, "banknote"); err != nil {
+		t.Errorf("SetPriceType: %v", err)
+	}
+	priceTypes, err := repo.GetPriceTypes()
+	if err != nil || len(priceTypes) != 1 || priceTypes[12345] != "banknote" {
+		t.Errorf("GetPriceTypes = %v, %v; want map[12345:banknote]", priceTypes, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
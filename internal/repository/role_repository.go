@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const RoleAdmin = "admin"
+
+type PostgresRoleRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresRoleRepository(db *sql.DB) *PostgresRoleRepository {
+	return &PostgresRoleRepository{db: db}
+}
+
+type RoleRepository interface {
+	HasRole(chatID int64, role string) (bool, error)
+}
+
+func (rr *PostgresRoleRepository) HasRole(chatID int64, role string) (bool, error) {
+	query := `
+	SELECT EXISTS (SELECT 1 FROM roles WHERE chat_id = $1 AND role = $2)
+	`
+
+	var exists bool
+	if err := rr.db.QueryRow(query, chatID, role).Scan(&exists); err != nil {
+		return false, fmt.Errorf("HasRole query: %w", err)
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPostgresRoleRepository_HasRole(t *testing.T) {
+	tests := []struct {
+		name                string
+		mockSetup           func(mock sqlmock.Sqlmock)
+		expected            bool
+		expectedErrorString string
+	}{
+		{
+			name: "HasRole",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"exists"}).AddRow(true)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM roles WHERE chat_id = $1 AND role = $2)")).
+					WithArgs(12345, RoleAdmin).WillReturnRows(rows)
+			},
+			expected: true,
+		},
+		{
+			name: "NoRole",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"exists"}).AddRow(false)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS")).WillReturnRows(rows)
+			},
+			expected: false,
+		},
+		{
+			name: "QueryError",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS")).WillReturnError(errors.New("ERROR"))
+			},
+			expectedErrorString: "HasRole query:",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbMock, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock :%v", err)
+			}
+			defer dbMock.Close()
+
+			tc.mockSetup(mock)
+
+			hasRole, err := NewPostgresRoleRepository(dbMock).HasRole(12345, RoleAdmin)
+
+			if tc.expectedErrorString == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got :%v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.expectedErrorString) {
+				t.Errorf("error = %v; want it to contain %s", err, tc.expectedErrorString)
+			}
+
+			if hasRole != tc.expected {
+				t.Errorf("hasRole = %v; want %v", hasRole, tc.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/models"
+)
+
+type PostgresStatsRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresStatsRepository(db *sql.DB) *PostgresStatsRepository {
+	return &PostgresStatsRepository{db: db}
+}
+
+type StatsRepository interface {
+	GetStats(day time.Time) (*models.Stats, error)
+	AddSent(day time.Time, count int) error
+}
+
+func (sr *PostgresStatsRepository) GetStats(day time.Time) (*models.Stats, error) {
+	query := `
+	SELECT
+		(SELECT COUNT(*) FROM users),
+		(SELECT COUNT(*) FROM users WHERE blocked_at IS NULL),
+		(SELECT COUNT(*) FROM users WHERE blocked_at IS NOT NULL),
+		COALESCE((SELECT sent FROM message_stats WHERE day = $1), 0)
+	`
+
+	var stats models.Stats
+	err := sr.db.QueryRow(query, day.Format(time.DateOnly)).Scan(
+		&stats.Subscribers,
+		&stats.Active,
+		&stats.Blocked,
+		&stats.SentToday,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("GetStats query: %w", err)
+	}
+
+	return &stats, nil
+}
+
+func (sr *PostgresStatsRepository) AddSent(day time.Time, count int) error {
+	query := `
+	INSERT INTO message_stats (day, sent) VALUES ($1, $2)
+	ON CONFLICT (day) DO UPDATE SET sent = message_stats.sent + EXCLUDED.sent
+	`
+
+	if _, err := sr.db.Exec(query, day.Format(time.DateOnly), count); err != nil {
+		return fmt.Errorf("AddSent exec: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/akyTheDev/currency-bot/internal/models"
+)
+
+var statsDay = time.Date(2025, 3, 4, 15, 0, 0, 0, time.UTC)
+
+func TestPostgresStatsRepository_GetStats(t *testing.T) {
+	tests := []struct {
+		name                string
+		mockSetup           func(mock sqlmock.Sqlmock)
+		expected            *models.Stats
+		expectedErrorString string
+	}{
+		{
+			name: "Success",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"subscribers", "active", "blocked", "sent"}).AddRow(10, 8, 2, 42)
+				mock.ExpectQuery(regexp.QuoteMeta("FROM message_stats WHERE day = $1")).
+					WithArgs("2025-03-04").WillReturnRows(rows)
+			},
+			expected: &models.Stats{Subscribers: 10, Active: 8, Blocked: 2, SentToday: 42},
+		},
+		{
+			name: "QueryError",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM message_stats")).WillReturnError(errors.New("ERROR"))
+			},
+			expectedErrorString: "GetStats query:",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbMock, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock :%v", err)
+			}
+			defer dbMock.Close()
+
+			tc.mockSetup(mock)
+
+			stats, err := NewPostgresStatsRepository(dbMock).GetStats(statsDay)
+
+			if tc.expectedErrorString == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got :%v", err)
+				}
+				if *stats != *tc.expected {
+					t.Errorf("stats = %+v; want %+v", stats, tc.expected)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.expectedErrorString) {
+				t.Errorf("error = %v; want it to contain %s", err, tc.expectedErrorString)
+			}
+		})
+	}
+}
+
+func TestPostgresStatsRepository_AddSent(t *testing.T) {
+	dbMock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock :%v", err)
+	}
+	defer dbMock.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO message_stats (day, sent) VALUES ($1, $2)")).
+		WithArgs("2025-03-04", 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO message_stats")).WillReturnError(errors.New("ERROR"))
+
+	repo := NewPostgresStatsRepository(dbMock)
+	if err := repo.AddSent(statsDay, 3); err != nil {
+		t.Errorf("Expected no error, got :%v", err)
+	}
+	if err := repo.AddSent(statsDay, 3); err == nil || !strings.Contains(err.Error(), "AddSent exec:") {
+		t.Errorf("error = %v; want it to contain AddSent exec:", err)
+	}
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/models"
+)
+
+type PostgresSubscriptionRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresSubscriptionRepository(db *sql.DB) *PostgresSubscriptionRepository {
+	return &PostgresSubscriptionRepository{db: db}
+}
+
+type SubscriptionRepository interface {
+	GetSubscriptions(chatID int64) ([]currency.Code, error)
+	GetAllSubscriptions() (map[int64][]currency.Code, error)
+	Subscribe(chatID int64, code currency.Code, actor string) error
+	Unsubscribe(chatID int64, code currency.Code, actor string) error
+}
+
+func (sr *PostgresSubscriptionRepository) GetSubscriptions(chatID int64) ([]currency.Code, error) {
+	query := `
+	SELECT currency FROM subscriptions WHERE chat_id = $1 ORDER BY currency
+	`
+
+	rows, err := sr.db.Query(query, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("GetSubscriptions query: %w", err)
+	}
+	defer rows.Close()
+
+	var currencies []currency.Code
+	for rows.Next() {
+		var code currency.Code
+		if err := rows.Scan(&code); err != nil {
+			return nil, fmt.Errorf("GetSubscriptions scan: %w", err)
+		}
+		currencies = append(currencies, code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetSubscriptions rows: %w", err)
+	}
+
+	return currencies, nil
+}
+
+func (sr *PostgresSubscriptionRepository) GetAllSubscriptions() (map[int64][]currency.Code, error) {
+	query := `
+	SELECT chat_id, currency FROM subscriptions ORDER BY chat_id, currency
+	`
+
+	rows, err := sr.db.Query(query)
+	if err != nil {
+		return nil, fmt.Errorf("GetAllSubscriptions query: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make(map[int64][]currency.Code)
+	for rows.Next() {
+		var chatID int64
+		var code currency.Code
+		if err := rows.Scan(&chatID, &code); err != nil {
+			return nil, fmt.Errorf("GetAllSubscriptions scan: %w", err)
+		}
+		subscriptions[chatID] = append(subscriptions[chatID], code)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetAllSubscriptions rows: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+func (sr *PostgresSubscriptionRepository) Subscribe(chatID int64, code currency.Code, actor string) error {
+	query := `
+	INSERT INTO subscriptions (chat_id, currency) VALUES ($1, $2)
+	ON CONFLICT (chat_id, currency) DO NOTHING
+	`
+	return sr.change("Subscribe", query, models.AuditSubscribe, domain.ErrAlreadySubscribed, chatID, code, actor)
+}
+
+func (sr *PostgresSubscriptionRepository) Unsubscribe(chatID int64, code currency.Code, actor string) error {
+	query := `
+	DELETE FROM subscriptions WHERE chat_id = $1 AND currency = $2
+	`
+	return sr.change("Unsubscribe", query, models.AuditUnsubscribe, domain.ErrNotSubscribed, chatID, code, actor)
+}
+
+// change runs a subscription mutation and its audit event in one transaction.
+func (sr *PostgresSubscriptionRepository) change(
+	op, query, eventType string, unchanged error, chatID int64, code currency.Code, actor string,
+) error {
+	tx, err := sr.db.Begin()
+	if err != nil {
+		return fmt.Errorf("%s begin: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query, chatID, code)
+	if err != nil {
+		return fmt.Errorf("%s exec: %w", op, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s rows affected: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return unchanged
+	}
+
+	payload, err := json.Marshal(map[string]currency.Code{"currency": code})
+	if err != nil {
+		return fmt.Errorf("%s payload: %w", op, err)
+	}
+
+	event := models.AuditEvent{Type: eventType, Actor: actor, ChatID: chatID, Payload: payload}
+	if err := insertAuditEvent(tx, event); err != nil {
+		return fmt.Errorf("%s audit: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s commit: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/domain"
+)
+
+func TestPostgresSubscriptionRepository_GetSubscriptions(t *testing.T) {
+	dbMock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock :%v", err)
+	}
+	defer dbMock.Close()
+
+	rows := sqlmock.NewRows([]string{"currency"}).AddRow("EUR").AddRow("USD")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT currency FROM subscriptions WHERE chat_id = $1")).
+		WithArgs(12345).WillReturnRows(rows)
+
+	currencies, err := NewPostgresSubscriptionRepository(dbMock).GetSubscriptions(12345)
+	if err != nil {
+		t.Fatalf("Expected no error, got :%v", err)
+	}
+	if strings.Join(currency.Strings(currencies), ",") != "EUR,USD" {
+		t.Errorf("currencies = %v; want [EUR USD]", currencies)
+	}
+}
+
+func TestPostgresSubscriptionRepository_GetAllSubscriptions(t *testing.T) {
+	dbMock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock :%v", err)
+	}
+	defer dbMock.Close()
+
+	rows := sqlmock.NewRows([]string{"chat_id", "currency"}).AddRow(1, "EUR").AddRow(1, "USD").AddRow(2, "GBP").
+		// DOGE was a configured coin that is no longer registered.
+		AddRow(2, "DOGE")
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT chat_id, currency FROM subscriptions")).WillReturnRows(rows)
+
+	subscriptions, err := NewPostgresSubscriptionRepository(dbMock).GetAllSubscriptions()
+	if err != nil {
+		t.Fatalf("Expected no error, got :%v", err)
+	}
+	if len(subscriptions) != 2 || len(subscriptions[1]) != 2 || len(subscriptions[2]) != 2 || subscriptions[2][1] != "DOGE" {
+		t.Errorf("subscriptions = %v; want map[1:[EUR USD] 2:[GBP DOGE]]", subscriptions)
+	}
+}
+
+func TestPostgresSubscriptionRepository_Change(t *testing.T) {
+	tests := []struct {
+		name                string
+		unsubscribe         bool
+		mockSetup           func(mock sqlmock.Sqlmock)
+		expectedErr         error
+		expectedErrorString string
+	}{
+		{
+			name: "Subscribe",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subscriptions (chat_id, currency) VALUES ($1, $2)")).
+					WithArgs(12345, "USD").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(auditInsertQuery)).
+					WithArgs("subscribe", "user:12345", 12345, `{"currency":"USD"}`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "AlreadySubscribed",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subscriptions")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrAlreadySubscribed,
+		},
+		{
+			name:        "Unsubscribe",
+			unsubscribe: true,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscriptions WHERE chat_id = $1 AND currency = $2")).
+					WithArgs(12345, "USD").WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(auditInsertQuery)).
+					WithArgs("unsubscribe", "user:12345", 12345, `{"currency":"USD"}`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:        "NotSubscribed",
+			unsubscribe: true,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("DELETE FROM subscriptions")).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+			expectedErr: domain.ErrNotSubscribed,
+		},
+		{
+			name: "AuditError",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO subscriptions")).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(auditInsertQuery)).WillReturnError(errors.New("ERROR"))
+				mock.ExpectRollback()
+			},
+			expectedErrorString: "Subscribe audit: ",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbMock, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock :%v", err)
+			}
+			defer dbMock.Close()
+
+			tc.mockSetup(mock)
+
+			repo := NewPostgresSubscriptionRepository(dbMock)
+			if tc.unsubscribe {
+				err = repo.Unsubscribe(12345, "USD", "user:12345")
+			} else {
+				err = repo.Subscribe(12345, "USD", "user:12345")
+			}
+
+			switch {
+			case tc.expectedErr != nil:
+				if !errors.Is(err, tc.expectedErr) {
+					t.Errorf("error = %v; want %v", err, tc.expectedErr)
+				}
+			case tc.expectedErrorString != "":
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErrorString) {
+					t.Errorf("error = %v; want it to contain %s", err, tc.expectedErrorString)
+				}
+			case err != nil:
+				t.Errorf("Expected no error, got :%v", err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
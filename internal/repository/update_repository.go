@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type PostgresUpdateRepository struct {
+	db *sql.DB
+}
+
+func NewPostgresUpdateRepository(db *sql.DB) *PostgresUpdateRepository {
+	return &PostgresUpdateRepository{db: db}
+}
+
+type UpdateRepository interface {
+	GetOffset() (int, error)
+	SaveOffset(updateID int) error
+	GetProcessedAfter(updateID int) ([]int, error)
+	MarkProcessed(updateID int) error
+	PruneProcessed(before time.Time) (int64, error)
+}
+
+// GetOffset returns the last update_id known to be handled, or 0 when none
+// has been stored yet.
+func (ur *PostgresUpdateRepository) GetOffset() (int, error) {
+	query := `
+	SELECT update_id FROM update_offset
+	`
+
+	var updateID int
+	err := ur.db.QueryRow(query).Scan(&updateID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("GetOffset query: %w", err)
+	}
+
+	return updateID, nil
+}
+
+// SaveOffset stores updateID unless a later one is already stored, so
+// concurrent workers can't move the offset backwards.
+func (ur *PostgresUpdateRepository) SaveOffset(updateID int) error {
+	query := `
+	INSERT INTO update_offset (update_id) VALUES ($1)
+	ON CONFLICT (id) DO UPDATE SET update_id = GREATEST(update_offset.update_id, EXCLUDED.update_id), updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := ur.db.Exec(query, updateID); err != nil {
+		return fmt.Errorf("SaveOffset exec: %w", err)
+	}
+	return nil
+}
+
+func (ur *PostgresUpdateRepository) GetProcessedAfter(updateID int) ([]int, error) {
+	query := `
+	SELECT update_id FROM processed_updates WHERE update_id > $1
+	`
+
+	rows, err := ur.db.Query(query, updateID)
+	if err != nil {
+		return nil, fmt.Errorf("GetProcessedAfter query: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("GetProcessedAfter scan: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetProcessedAfter rows: %w", err)
+	}
+	return ids, nil
+}
+
+func (ur *PostgresUpdateRepository) MarkProcessed(updateID int) error {
+	query := `
+	INSERT INTO processed_updates (update_id) VALUES ($1) ON CONFLICT (update_id) DO NOTHING
+	`
+
+	if _, err := ur.db.Exec(query, updateID); err != nil {
+		return fmt.Errorf("MarkProcessed exec: %w", err)
+	}
+	return nil
+}
+
+func (ur *PostgresUpdateRepository) PruneProcessed(before time.Time) (int64, error) {
+	query := `
+	DELETE FROM processed_updates WHERE processed_at < $1
+	`
+
+	result, err := ur.db.Exec(query, before)
+	if err != nil {
+		return 0, fmt.Errorf("PruneProcessed exec: %w", err)
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestPostgresUpdateRepository_GetOffset(t *testing.T) {
+	tests := []struct {
+		name                string
+		mockSetup           func(mock sqlmock.Sqlmock)
+		expected            int
+		expectedErrorString string
+	}{
+		{
+			name: "Stored",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"update_id"}).AddRow(42)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT update_id FROM update_offset")).WillReturnRows(rows)
+			},
+			expected: 42,
+		},
+		{
+			name: "NotStored",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM update_offset")).WillReturnError(sql.ErrNoRows)
+			},
+			expected: 0,
+		},
+		{
+			name: "QueryError",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("FROM update_offset")).WillReturnError(errors.New("ERROR"))
+			},
+			expectedErrorString: "GetOffset query:",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbMock, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock :%v", err)
+			}
+			defer dbMock.Close()
+
+			tc.mockSetup(mock)
+
+			offset, err := NewPostgresUpdateRepository(dbMock).GetOffset()
+
+			if tc.expectedErrorString != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErrorString) {
+					t.Errorf("error = %v; want it to contain %s", err, tc.expectedErrorString)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got :%v", err)
+			}
+			if offset != tc.expected {
+				t.Errorf("offset = %d; want %d", offset, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPostgresUpdateRepository_Processed(t *testing.T) {
+	dbMock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock :%v", err)
+	}
+	defer dbMock.Close()
+
+	before := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta("GREATEST(update_offset.update_id, EXCLUDED.update_id)")).
+		WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO processed_updates (update_id) VALUES ($1) ON CONFLICT (update_id) DO NOTHING")).
+		WithArgs(7).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT update_id FROM processed_updates WHERE update_id > $1")).
+		WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"update_id"}).AddRow(7).AddRow(9))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM processed_updates WHERE processed_at < $1")).
+		WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	repo := NewPostgresUpdateRepository(dbMock)
+	if err := repo.SaveOffset(7); err != nil {
+		t.Errorf("SaveOffset: %v", err)
+	}
+	if err := repo.MarkProcessed(7); err != nil {
+		t.Errorf("MarkProcessed: %v", err)
+	}
+	if ids, err := repo.GetProcessedAfter(5); err != nil || len(ids) != 2 || ids[0] != 7 || ids[1] != 9 {
+		t.Errorf("GetProcessedAfter = %v, %v; want [7 9]", ids, err)
+	}
+	if removed, err := repo.PruneProcessed(before); err != nil || removed != 3 {
+		t.Errorf("PruneProcessed = %d, %v; want 3", removed, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/akyTheDev/currency-bot/internal/domain"
@@ -17,17 +19,29 @@ func NewPostgresUserRepository(db *sql.DB) *PostgresUserRepository {
 }
 
 type UserRepository interface {
-	CreateUser(chatID int64) error
-	DeleteUser(chatID int64) error
+	CreateUser(chatID int64, actor string) error
+	DeleteUser(chatID int64, actor string) error
 	GetAllUsers() ([]models.User, error)
+	GetActiveUsers() ([]models.User, error)
+	GetUser(chatID int64) (*models.User, error)
+	CountUsers() (int, error)
+	CountActiveUsers() (int, error)
+	BlockUser(chatID int64, reason string) error
 }
 
-func (ur *PostgresUserRepository) CreateUser(chatID int64) error {
+func (ur *PostgresUserRepository) CreateUser(chatID int64, actor string) error {
 	query := `
 	INSERT INTO users (chat_id) VALUES ($1)
-	ON CONFLICT (chat_id) DO NOTHING
+	ON CONFLICT (chat_id) DO UPDATE SET blocked_at = NULL, updated_at = CURRENT_TIMESTAMP
+	WHERE users.blocked_at IS NOT NULL
 	`
-	result, err := ur.db.Exec(
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return fmt.Errorf("CreateUser begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
 		query,
 		chatID,
 	)
@@ -45,14 +59,29 @@ func (ur *PostgresUserRepository) CreateUser(chatID int64) error {
 		return domain.ErrUserAlreadyExists
 	}
 
+	event := models.AuditEvent{Type: models.AuditRegister, Actor: actor, ChatID: chatID}
+	if err := insertAuditEvent(tx, event); err != nil {
+		return fmt.Errorf("CreateUser audit: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("CreateUser commit: %w", err)
+	}
+
 	return nil
 }
 
-func (ur *PostgresUserRepository) DeleteUser(chatID int64) error {
+func (ur *PostgresUserRepository) DeleteUser(chatID int64, actor string) error {
 	query := `
 		DELETE FROM users WHERE chat_id = $1
 	`
-	result, err := ur.db.Exec(
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return fmt.Errorf("DeleteUser begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(
 		query,
 		chatID,
 	)
@@ -70,6 +99,15 @@ func (ur *PostgresUserRepository) DeleteUser(chatID int64) error {
 		return domain.ErrUserNotFound
 	}
 
+	event := models.AuditEvent{Type: models.AuditDelete, Actor: actor, ChatID: chatID}
+	if err := insertAuditEvent(tx, event); err != nil {
+		return fmt.Errorf("DeleteUser audit: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("DeleteUser commit: %w", err)
+	}
+
 	return nil
 }
 
@@ -78,9 +116,31 @@ func (ur *PostgresUserRepository) GetAllUsers() ([]models.User, error) {
 	SELECT id, chat_id FROM users
 	`
 
-	rows, err := ur.db.Query(query)
+	users, err := ur.queryUsers(query)
 	if err != nil {
-		return nil, fmt.Errorf("GetAllUsers query: %w", err)
+		return nil, fmt.Errorf("GetAllUsers %w", err)
+	}
+
+	return users, nil
+}
+
+func (ur *PostgresUserRepository) GetActiveUsers() ([]models.User, error) {
+	query := `
+	SELECT id, chat_id FROM users WHERE blocked_at IS NULL
+	`
+
+	users, err := ur.queryUsers(query)
+	if err != nil {
+		return nil, fmt.Errorf("GetActiveUsers %w", err)
+	}
+
+	return users, nil
+}
+
+func (ur *PostgresUserRepository) queryUsers(query string, args ...any) ([]models.User, error) {
+	rows, err := ur.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %w", err)
 	}
 	defer rows.Close()
 
@@ -93,10 +153,100 @@ func (ur *PostgresUserRepository) GetAllUsers() ([]models.User, error) {
 			&user.ChatID,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("GetAllUsers scan: %w", err)
+			return nil, fmt.Errorf("scan: %w", err)
 		}
 		users = append(users, user)
 	}
 
 	return users, nil
 }
+
+func (ur *PostgresUserRepository) GetUser(chatID int64) (*models.User, error) {
+	query := `
+	SELECT id, chat_id, created_at, blocked_at FROM users WHERE chat_id = $1
+	`
+
+	var user models.User
+	err := ur.db.QueryRow(query, chatID).Scan(
+		&user.ID,
+		&user.ChatID,
+		&user.CreatedAt,
+		&user.BlockedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrUserNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetUser query: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (ur *PostgresUserRepository) BlockUser(chatID int64, reason string) error {
+	query := `
+	UPDATE users SET blocked_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+	WHERE chat_id = $1 AND blocked_at IS NULL
+	`
+	tx, err := ur.db.Begin()
+	if err != nil {
+		return fmt.Errorf("BlockUser begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query, chatID)
+	if err != nil {
+		return fmt.Errorf("BlockUser exec: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("BlockUser rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]string{"reason": reason})
+	if err != nil {
+		return fmt.Errorf("BlockUser payload: %w", err)
+	}
+
+	event := models.AuditEvent{Type: models.AuditDeactivate, Actor: models.ActorSystem, ChatID: chatID, Payload: payload}
+	if err := insertAuditEvent(tx, event); err != nil {
+		return fmt.Errorf("BlockUser audit: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("BlockUser commit: %w", err)
+	}
+
+	return nil
+}
+
+func (ur *PostgresUserRepository) CountUsers() (int, error) {
+	query := `
+	SELECT COUNT(*) FROM users
+	`
+
+	var count int
+	if err := ur.db.QueryRow(query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("CountUsers query: %w", err)
+	}
+
+	return count, nil
+}
+
+func (ur *PostgresUserRepository) CountActiveUsers() (int, error) {
+	query := `
+	SELECT COUNT(*) FROM users WHERE blocked_at IS NULL
+	`
+
+	var count int
+	if err := ur.db.QueryRow(query).Scan(&count); err != nil {
+		return 0, fmt.Errorf("CountActiveUsers query: %w", err)
+	}
+
+	return count, nil
+}
@@ -1,15 +1,22 @@
 package repository
 
 import (
+	"database/sql"
 	"errors"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/akyTheDev/currency-bot/internal/models"
 )
 
+const createUserQuery = `INSERT INTO users (chat_id) VALUES ($1)
+	ON CONFLICT (chat_id) DO UPDATE SET blocked_at = NULL`
+
+const auditInsertQuery = `INSERT INTO audit_events (type, actor, chat_id, payload) VALUES ($1, $2, $3, $4::jsonb)`
+
 func TestPosgresUserRepository_CreateUser(t *testing.T) {
 	tests := []struct {
 		name                string
@@ -21,10 +28,11 @@ func TestPosgresUserRepository_CreateUser(t *testing.T) {
 			name:   "Success",
 			chatID: 12345,
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec(regexp.QuoteMeta(
-					`INSERT INTO users (chat_id) VALUES ($1)
-	ON CONFLICT (chat_id) DO NOTHING`,
-				)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(createUserQuery)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(auditInsertQuery)).
+					WithArgs("register", "user:12345", 12345, "{}").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			expectedErrorString: "",
 		},
@@ -32,21 +40,27 @@ func TestPosgresUserRepository_CreateUser(t *testing.T) {
 			name:   "AlreadyExists",
 			chatID: 12345,
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec(regexp.QuoteMeta(
-					`INSERT INTO users (chat_id) VALUES ($1)
-	ON CONFLICT (chat_id) DO NOTHING`,
-				)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(createUserQuery)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
 			},
 			expectedErrorString: "already exists",
 		},
+		{
+			name:   "BeginError",
+			chatID: 12345,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().WillReturnError(errors.New("ERROR"))
+			},
+			expectedErrorString: "CreateUser begin: ",
+		},
 		{
 			name:   "ExecError",
 			chatID: 12345,
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec(regexp.QuoteMeta(
-					`INSERT INTO users (chat_id) VALUES ($1)
-	ON CONFLICT (chat_id) DO NOTHING`,
-				)).WillReturnError(errors.New("ERROR"))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(createUserQuery)).WillReturnError(errors.New("ERROR"))
+				mock.ExpectRollback()
 			},
 			expectedErrorString: "CreateUser exec: ",
 		},
@@ -54,13 +68,23 @@ func TestPosgresUserRepository_CreateUser(t *testing.T) {
 			name:   "RowsAffectedError",
 			chatID: 12345,
 			mockSetup: func(mock sqlmock.Sqlmock) {
-				mock.ExpectExec(regexp.QuoteMeta(
-					`INSERT INTO users (chat_id) VALUES ($1)
-	ON CONFLICT (chat_id) DO NOTHING`,
-				)).WillReturnResult(sqlmock.NewErrorResult(errors.New("rowsAffected failed")))
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(createUserQuery)).WillReturnResult(sqlmock.NewErrorResult(errors.New("rowsAffected failed")))
+				mock.ExpectRollback()
 			},
 			expectedErrorString: "CreateUser rows affected: ",
 		},
+		{
+			name:   "AuditError",
+			chatID: 12345,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(createUserQuery)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(auditInsertQuery)).WillReturnError(errors.New("ERROR"))
+				mock.ExpectRollback()
+			},
+			expectedErrorString: "CreateUser audit: ",
+		},
 	}
 
 	for _, tc := range tests {
@@ -74,7 +98,7 @@ func TestPosgresUserRepository_CreateUser(t *testing.T) {
 			tc.mockSetup(mock)
 
 			repo := NewPostgresUserRepository(dbMock)
-			err = repo.CreateUser(tc.chatID)
+			err = repo.CreateUser(tc.chatID, models.UserActor(tc.chatID))
 
 			if tc.expectedErrorString == "" {
 				if err != nil {
@@ -82,12 +106,16 @@ func TestPosgresUserRepository_CreateUser(t *testing.T) {
 				}
 			} else {
 				if err == nil {
-					t.Errorf("Expected error string %s, got nil", tc.expectedErrorString)
+					t.Fatalf("Expected error string %s, got nil", tc.expectedErrorString)
 				}
 				if !strings.Contains(err.Error(), tc.expectedErrorString) {
 					t.Errorf("error = %q; want it to contain %s", err.Error(), tc.expectedErrorString)
 				}
 			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
 		})
 	}
 }
@@ -103,9 +131,13 @@ func TestPosgresUserRepository_DeleteUser(t *testing.T) {
 			name:   "Success",
 			chatID: 12345,
 			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta(
 					`DELETE FROM users WHERE chat_id = $1`,
 				)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(auditInsertQuery)).
+					WithArgs("delete", "cli", 12345, "{}").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
 			},
 			expectedErrorString: "",
 		},
@@ -113,9 +145,11 @@ func TestPosgresUserRepository_DeleteUser(t *testing.T) {
 			name:   "NoRowsError",
 			chatID: 12345,
 			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta(
 					`DELETE FROM users WHERE chat_id = $1`,
 				)).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
 			},
 			expectedErrorString: "not found",
 		},
@@ -123,9 +157,11 @@ func TestPosgresUserRepository_DeleteUser(t *testing.T) {
 			name:   "ExecError",
 			chatID: 12345,
 			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta(
 					`DELETE FROM users WHERE chat_id = $1`,
 				)).WillReturnError(errors.New("ERROR"))
+				mock.ExpectRollback()
 			},
 			expectedErrorString: "DeleteUser exec: ",
 		},
@@ -133,12 +169,27 @@ func TestPosgresUserRepository_DeleteUser(t *testing.T) {
 			name:   "RowsAffectedError",
 			chatID: 12345,
 			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
 				mock.ExpectExec(regexp.QuoteMeta(
 					`DELETE FROM users WHERE chat_id = $1`,
 				)).WillReturnResult(sqlmock.NewErrorResult(errors.New("rowsAffected failed")))
+				mock.ExpectRollback()
 			},
 			expectedErrorString: "DeleteUser rows affected: ",
 		},
+		{
+			name:   "CommitError",
+			chatID: 12345,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(
+					`DELETE FROM users WHERE chat_id = $1`,
+				)).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(auditInsertQuery)).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit().WillReturnError(errors.New("ERROR"))
+			},
+			expectedErrorString: "DeleteUser commit: ",
+		},
 	}
 
 	for _, tc := range tests {
@@ -152,7 +203,7 @@ func TestPosgresUserRepository_DeleteUser(t *testing.T) {
 			tc.mockSetup(mock)
 
 			repo := NewPostgresUserRepository(dbMock)
-			err = repo.DeleteUser(tc.chatID)
+			err = repo.DeleteUser(tc.chatID, models.ActorCLI)
 
 			if tc.expectedErrorString == "" {
 				if err != nil {
@@ -160,12 +211,16 @@ func TestPosgresUserRepository_DeleteUser(t *testing.T) {
 				}
 			} else {
 				if err == nil {
-					t.Errorf("Expected error string %s, got nil", tc.expectedErrorString)
+					t.Fatalf("Expected error string %s, got nil", tc.expectedErrorString)
 				}
 				if !strings.Contains(err.Error(), tc.expectedErrorString) {
 					t.Errorf("error = %q; want it to contain %s", err.Error(), tc.expectedErrorString)
 				}
 			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
 		})
 	}
 }
@@ -248,3 +303,238 @@ func TestPosgresUserRepository_GetAllUsers(t *testing.T) {
 		})
 	}
 }
+
+func TestPostgresUserRepository_CountActiveUsers(t *testing.T) {
+	dbMock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock :%v", err)
+	}
+	defer dbMock.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users WHERE blocked_at IS NULL")).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	count, err := NewPostgresUserRepository(dbMock).CountActiveUsers()
+	if err != nil || count != 5 {
+		t.Errorf("CountActiveUsers = %d, %v; want 5, nil", count, err)
+	}
+}
+
+func TestPosgresUserRepository_CountUsers(t *testing.T) {
+	tests := []struct {
+		name                string
+		mockSetup           func(mock sqlmock.Sqlmock)
+		expected            int
+		expectedErrorString string
+	}{
+		{
+			name: "Success",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"count"}).AddRow(42)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users")).WillReturnRows(rows)
+			},
+			expected:            42,
+			expectedErrorString: "",
+		},
+		{
+			name: "QueryError",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM users")).WillReturnError(errors.New("ERROR"))
+			},
+			expected:            0,
+			expectedErrorString: "CountUsers query:",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbMock, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock :%v", err)
+			}
+			defer dbMock.Close()
+
+			tc.mockSetup(mock)
+
+			repo := NewPostgresUserRepository(dbMock)
+			count, err := repo.CountUsers()
+
+			if tc.expectedErrorString == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got :%v", err)
+				}
+			} else {
+				if err == nil {
+					t.Fatalf("Expected error string %s, got nil", tc.expectedErrorString)
+				}
+				if !strings.Contains(err.Error(), tc.expectedErrorString) {
+					t.Errorf("error = %q; want it to contain %s", err.Error(), tc.expectedErrorString)
+				}
+			}
+
+			if count != tc.expected {
+				t.Errorf("count = %d; want %d", count, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPosgresUserRepository_GetActiveUsers(t *testing.T) {
+	dbMock, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to create sqlmock :%v", err)
+	}
+	defer dbMock.Close()
+
+	rows := sqlmock.NewRows([]string{"id", "chat_id"}).AddRow(123, 1230)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, chat_id FROM users WHERE blocked_at IS NULL")).WillReturnRows(rows)
+
+	users, err := NewPostgresUserRepository(dbMock).GetActiveUsers()
+	if err != nil {
+		t.Fatalf("Expected no error, got :%v", err)
+	}
+	if len(users) != 1 || users[0] != (models.User{ID: 123, ChatID: 1230}) {
+		t.Errorf("users = %+v; want [{ID:123 ChatID:1230}]", users)
+	}
+}
+
+func TestPosgresUserRepository_GetUser(t *testing.T) {
+	createdAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	blockedAt := createdAt.Add(time.Hour)
+
+	tests := []struct {
+		name                string
+		mockSetup           func(mock sqlmock.Sqlmock)
+		expected            *models.User
+		expectedErrorString string
+	}{
+		{
+			name: "Success",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"id", "chat_id", "created_at", "blocked_at"}).AddRow(1, 12345, createdAt, blockedAt)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, chat_id, created_at, blocked_at FROM users WHERE chat_id = $1")).
+					WithArgs(12345).WillReturnRows(rows)
+			},
+			expected: &models.User{ID: 1, ChatID: 12345, CreatedAt: createdAt, BlockedAt: &blockedAt},
+		},
+		{
+			name: "NotFound",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, chat_id, created_at, blocked_at FROM users")).WillReturnError(sql.ErrNoRows)
+			},
+			expectedErrorString: "not found",
+		},
+		{
+			name: "QueryError",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, chat_id, created_at, blocked_at FROM users")).WillReturnError(errors.New("ERROR"))
+			},
+			expectedErrorString: "GetUser query:",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbMock, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock :%v", err)
+			}
+			defer dbMock.Close()
+
+			tc.mockSetup(mock)
+
+			user, err := NewPostgresUserRepository(dbMock).GetUser(12345)
+
+			if tc.expectedErrorString == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got :%v", err)
+				}
+				if user.ID != tc.expected.ID || user.ChatID != tc.expected.ChatID || !user.CreatedAt.Equal(tc.expected.CreatedAt) ||
+					user.BlockedAt == nil || !user.BlockedAt.Equal(*tc.expected.BlockedAt) {
+					t.Errorf("user = %+v; want %+v", user, tc.expected)
+				}
+			} else {
+				if err == nil {
+					t.Fatalf("Expected error string %s, got nil", tc.expectedErrorString)
+				}
+				if !strings.Contains(err.Error(), tc.expectedErrorString) {
+					t.Errorf("error = %q; want it to contain %s", err.Error(), tc.expectedErrorString)
+				}
+			}
+		})
+	}
+}
+
+func TestPosgresUserRepository_BlockUser(t *testing.T) {
+	const blockQuery = "UPDATE users SET blocked_at = CURRENT_TIMESTAMP"
+
+	tests := []struct {
+		name                string
+		mockSetup           func(mock sqlmock.Sqlmock)
+		expectedErrorString string
+	}{
+		{
+			name: "Success",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(blockQuery)).WithArgs(12345).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(auditInsertQuery)).
+					WithArgs("deactivate", "system", 12345, `{"reason":"blocked"}`).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name: "AlreadyBlocked",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(blockQuery)).WithArgs(12345).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "ExecError",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(blockQuery)).WithArgs(12345).WillReturnError(errors.New("ERROR"))
+				mock.ExpectRollback()
+			},
+			expectedErrorString: "BlockUser exec:",
+		},
+		{
+			name: "AuditError",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(regexp.QuoteMeta(blockQuery)).WithArgs(12345).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec(regexp.QuoteMeta(auditInsertQuery)).WillReturnError(errors.New("ERROR"))
+				mock.ExpectRollback()
+			},
+			expectedErrorString: "BlockUser audit:",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dbMock, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to create sqlmock :%v", err)
+			}
+			defer dbMock.Close()
+
+			tc.mockSetup(mock)
+
+			err = NewPostgresUserRepository(dbMock).BlockUser(12345, "blocked")
+
+			if tc.expectedErrorString == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got :%v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tc.expectedErrorString) {
+				t.Errorf("error = %v; want it to contain %s", err, tc.expectedErrorString)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
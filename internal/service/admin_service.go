@@ -0,0 +1,85 @@
+package service
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/models"
+	"github.com/akyTheDev/currency-bot/internal/repository"
+)
+
+type AdminService struct {
+	roleRepo  repository.RoleRepository
+	statsRepo repository.StatsRepository
+	auditRepo repository.AuditRepository
+	logger    *log.Logger
+}
+
+func NewAdminService(
+	roleRepo repository.RoleRepository,
+	statsRepo repository.StatsRepository,
+	auditRepo repository.AuditRepository,
+	logger *log.Logger,
+) *AdminService {
+	return &AdminService{roleRepo: roleRepo, statsRepo: statsRepo, auditRepo: auditRepo, logger: logger}
+}
+
+func (s *AdminService) IsAdmin(chatID int64) bool {
+	isAdmin, err := s.roleRepo.HasRole(chatID, repository.RoleAdmin)
+	if err != nil {
+		s.logger.Printf("ERROR: AdminService:IsAdmin: %v\n", err)
+		return false
+	}
+	return isAdmin
+}
+
+func (s *AdminService) Stats() (*models.Stats, error) {
+	stats, err := s.statsRepo.GetStats(time.Now())
+	if err != nil {
+		s.logger.Printf("ERROR: AdminService:Stats: %v\n", err)
+		return nil, domain.ErrGeneric
+	}
+	return stats, nil
+}
+
+func (s *AdminService) RecordSent(count int) {
+	if count == 0 {
+		return
+	}
+	if err := s.statsRepo.AddSent(time.Now(), count); err != nil {
+		s.logger.Printf("ERROR: AdminService:RecordSent: %v\n", err)
+	}
+}
+
+func (s *AdminService) RecordAction(eventType string, userID, chatID int64, command, args string) {
+	payload, err := json.Marshal(map[string]string{"command": command, "args": args})
+	if err != nil {
+		s.logger.Printf("ERROR: AdminService:RecordAction: %v\n", err)
+		return
+	}
+
+	event := models.AuditEvent{
+		Type:    eventType,
+		Actor:   models.UserActor(userID),
+		ChatID:  chatID,
+		Payload: payload,
+	}
+	if eventType == models.AuditAdminAction {
+		event.Actor = models.AdminActor(userID)
+	}
+
+	if err := s.auditRepo.Record(event); err != nil {
+		s.logger.Printf("ERROR: AdminService:RecordAction: %v\n", err)
+	}
+}
+
+func (s *AdminService) AuditEvents(filter models.AuditFilter) ([]models.AuditEvent, error) {
+	events, err := s.auditRepo.ListAuditEvents(filter)
+	if err != nil {
+		s.logger.Printf("ERROR: AdminService:AuditEvents: %v\n", err)
+		return nil, domain.ErrGeneric
+	}
+	return events, nil
+}
@@ -0,0 +1,177 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/models"
+)
+
+type fakeRoleRepo struct {
+	hasRole  bool
+	err      error
+	lastRole string
+}
+
+func (f *fakeRoleRepo) HasRole(chatID int64, role string) (bool, error) {
+	f.lastRole = role
+	return f.hasRole, f.err
+}
+
+type fakeStatsRepo struct {
+	stats     *models.Stats
+	statsErr  error
+	addErr    error
+	addCalls  int
+	lastCount int
+}
+
+func (f *fakeStatsRepo) GetStats(day time.Time) (*models.Stats, error) {
+	return f.stats, f.statsErr
+}
+
+func (f *fakeStatsRepo) AddSent(day time.Time, count int) error {
+	f.addCalls++
+	f.lastCount = count
+	return f.addErr
+}
+
+type fakeAuditRepo struct {
+	events    []models.AuditEvent
+	recordErr error
+	listErr   error
+	recorded  []models.AuditEvent
+}
+
+func (f *fakeAuditRepo) Record(event models.AuditEvent) error {
+	f.recorded = append(f.recorded, event)
+	return f.recordErr
+}
+
+func (f *fakeAuditRepo) ListAuditEvents(filter models.AuditFilter) ([]models.AuditEvent, error) {
+	return f.events, f.listErr
+}
+
+func TestAdminServiceIsAdmin(t *testing.T) {
+	tests := []struct {
+		name     string
+		hasRole  bool
+		repoErr  error
+		expected bool
+	}{
+		{name: "Admin", hasRole: true, expected: true},
+		{name: "NotAdmin", hasRole: false, expected: false},
+		{name: "RepoError", hasRole: true, repoErr: errors.New("db failed"), expected: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			roles := &fakeRoleRepo{hasRole: tc.hasRole, err: tc.repoErr}
+			s := NewAdminService(roles, &fakeStatsRepo{}, &fakeAuditRepo{}, logger)
+
+			if got := s.IsAdmin(12345); got != tc.expected {
+				t.Errorf("IsAdmin() = %v; want %v", got, tc.expected)
+			}
+			if roles.lastRole != "admin" {
+				t.Errorf("role = %q; want %q", roles.lastRole, "admin")
+			}
+		})
+	}
+}
+
+func TestAdminServiceStats(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoStats   *models.Stats
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			name:      "Success",
+			repoStats: &models.Stats{Subscribers: 3, Active: 2, Blocked: 1, SentToday: 9},
+		},
+		{
+			name:        "RepoError",
+			repoErr:     errors.New("db failed"),
+			expectedErr: domain.ErrGeneric,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewAdminService(&fakeRoleRepo{}, &fakeStatsRepo{stats: tc.repoStats, statsErr: tc.repoErr}, &fakeAuditRepo{}, logger)
+
+			stats, err := s.Stats()
+
+			if err != tc.expectedErr {
+				t.Fatalf("Expected error: %v, got %v", tc.expectedErr, err)
+			}
+			if tc.repoStats != nil && *stats != *tc.repoStats {
+				t.Errorf("stats = %+v; want %+v", stats, tc.repoStats)
+			}
+		})
+	}
+}
+
+func TestAdminServiceRecordSent(t *testing.T) {
+	stats := &fakeStatsRepo{addErr: errors.New("db failed")}
+	s := NewAdminService(&fakeRoleRepo{}, stats, &fakeAuditRepo{}, logger)
+
+	s.RecordSent(0)
+	if stats.addCalls != 0 {
+		t.Errorf("AddSent called %d times for zero messages; want 0", stats.addCalls)
+	}
+
+	s.RecordSent(5)
+	if stats.addCalls != 1 || stats.lastCount != 5 {
+		t.Errorf("AddSent calls=%d count=%d; want 1 call with 5", stats.addCalls, stats.lastCount)
+	}
+}
+
+func TestAdminServiceRecordAction(t *testing.T) {
+	tests := []struct {
+		name          string
+		eventType     string
+		expectedActor string
+	}{
+		{name: "AdminAction", eventType: models.AuditAdminAction, expectedActor: "admin:7"},
+		{name: "Unauthorized", eventType: models.AuditUnauthorized, expectedActor: "user:7"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			audit := &fakeAuditRepo{recordErr: errors.New("db failed")}
+			s := NewAdminService(&fakeRoleRepo{}, &fakeStatsRepo{}, audit, logger)
+
+			s.RecordAction(tc.eventType, 7, 9, "stats", "")
+
+			if len(audit.recorded) != 1 {
+				t.Fatalf("recorded %d events; want 1", len(audit.recorded))
+			}
+			event := audit.recorded[0]
+			if event.Type != tc.eventType || event.Actor != tc.expectedActor || event.ChatID != 9 {
+				t.Errorf("event = %+v; want type %s actor %s chat 9", event, tc.eventType, tc.expectedActor)
+			}
+			if string(event.Payload) != `{"args":"","command":"stats"}` {
+				t.Errorf("payload = %s", event.Payload)
+			}
+		})
+	}
+}
+
+func TestAdminServiceAuditEvents(t *testing.T) {
+	events := []models.AuditEvent{{ID: 1, Type: models.AuditRegister}}
+
+	s := NewAdminService(&fakeRoleRepo{}, &fakeStatsRepo{}, &fakeAuditRepo{events: events}, logger)
+	got, err := s.AuditEvents(models.AuditFilter{})
+	if err != nil || len(got) != 1 {
+		t.Errorf("AuditEvents() = %+v, %v; want one event", got, err)
+	}
+
+	s = NewAdminService(&fakeRoleRepo{}, &fakeStatsRepo{}, &fakeAuditRepo{listErr: errors.New("db failed")}, logger)
+	if _, err := s.AuditEvents(models.AuditFilter{}); err != domain.ErrGeneric {
+		t.Errorf("expected ErrGeneric, got %v", err)
+	}
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"log"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	"github.com/akyTheDev/currency-bot/internal/models"
+	"github.com/akyTheDev/currency-bot/internal/repository"
+)
+
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+
+	// digestLookback covers a week of history before the close plus the
+	// close a week earlier, with room for weekends and holidays.
+	digestLookback = 15
+	digestWeek     = 7
+)
+
+// Istanbul is the time zone TCMB publishes in; Turkey keeps UTC+3 all year.
+var Istanbul = time.FixedZone("TRT", 3*60*60)
+
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// CurrencyDigest summarizes the recent selling rates of one currency.
+type CurrencyDigest struct {
+	Code      currency.Code
+	CloseDate time.Time
+	Close     decimal.Decimal
+	// DayChange and WeekChange are percentages; Has* is false when there is
+	// no history to compare with.
+	DayChange     decimal.Decimal
+	HasDayChange  bool
+	WeekChange    decimal.Decimal
+	HasWeekChange bool
+	WeekHigh      decimal.Decimal
+	WeekLow       decimal.Decimal
+	Sparkline     string
+}
+
+type DigestService struct {
+	historyRepo repository.HistoryRepository
+	logger      *log.Logger
+}
+
+func NewDigestService(historyRepo repository.HistoryRepository, logger *log.Logger) *DigestService {
+	return &DigestService{historyRepo: historyRepo, logger: logger}
+}
+
+// Day returns the Istanbul calendar day of t as midnight UTC, the form
+// stored in DATE columns.
+func Day(t time.Time) time.Time {
+	y, m, d := t.In(Istanbul).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// DigestDue reports whether a digest of kind goes out on day: daily ones
+// every day, weekly ones on Mondays.
+func DigestDue(kind string, day time.Time) bool {
+	switch kind {
+	case DigestDaily:
+		return true
+	case DigestWeekly:
+		return day.Weekday() == time.Monday
+	}
+	return false
+}
+
+// Record stores rates as the latest ones of the day their bulletin is
+// effective for, or of the day at falls on when they carry no date. Rates
+// without a code can't be looked up later and are left out.
+func (s *DigestService) Record(rates []fetcher.Rate, at time.Time) {
+	history := make([]models.HistoricalRate, 0, len(rates))
+	for _, r := range rates {
+		if r.Code == "" {
+			continue
+		}
+		day := r.Date
+		if day.IsZero() {
+			day = Day(at)
+		}
+		history = append(history, models.HistoricalRate{
+			Code: r.Code, Date: day, Unit: r.Unit, Buying: r.Buying, Selling: r.Selling,
+			BanknoteBuying: r.BanknoteBuying, BanknoteSelling: r.BanknoteSelling,
+		})
+	}
+
+	if err := s.historyRepo.Record(history); err != nil {
+		s.logger.Printf("ERROR: DigestService:Record: %v\n", err)
+	}
+}
+
+// Digest summarizes codes from the history recorded before day, in the order
+// of codes and with the prices of priceType. Currencies without history are
+// left out.
+func (s *DigestService) Digest(codes []currency.Code, day time.Time, priceType string) ([]CurrencyDigest, error) {
+	history, err := s.historyRepo.GetHistory(day.AddDate(0, 0, -digestLookback), day.AddDate(0, 0, -1))
+	if err != nil {
+		s.logger.Printf("ERROR: DigestService:Digest: %v\n", err)
+		return nil, domain.ErrGeneric
+	}
+
+	byCode := make(map[currency.Code][]models.HistoricalRate)
+	for _, r := range history {
+		byCode[r.Code] = append(byCode[r.Code], withPrices(r, priceType))
+	}
+
+	var digests []CurrencyDigest
+	for _, code := range codes {
+		if rates := byCode[code]; len(rates) > 0 {
+			digests = append(digests, summarize(code, rates))
+		}
+	}
+	return digests, nil
+}
+
+// withPrices is fetcher.Rate.WithPrices for recorded rates; days without
+// banknote prices keep the forex ones.
+func withPrices(r models.HistoricalRate, priceType string) models.HistoricalRate {
+	if priceType == fetcher.PriceBanknote && !r.BanknoteBuying.IsZero() && !r.BanknoteSelling.IsZero() {
+		r.Buying, r.Selling = r.BanknoteBuying, r.BanknoteSelling
+	}
+	return r
+}
+
+// summarize builds the digest of rates, which are ordered by date.
+func summarize(code currency.Code, rates []models.HistoricalRate) CurrencyDigest {
+	last := rates[len(rates)-1]
+	d := CurrencyDigest{Code: code, CloseDate: last.Date, Close: last.Selling}
+
+	if len(rates) > 1 {
+		d.DayChange, d.HasDayChange = percentChange(rates[len(rates)-2].Selling, last.Selling), true
+	}
+
+	weekAgo := last.Date.AddDate(0, 0, -digestWeek)
+	if i := slices.IndexFunc(rates, func(r models.HistoricalRate) bool { return r.Date.After(weekAgo) }); i > 0 {
+		d.WeekChange, d.HasWeekChange = percentChange(rates[i-1].Selling, last.Selling), true
+		rates = rates[i:]
+	}
+
+	values := make([]float64, len(rates))
+	for i, r := range rates {
+		values[i] = r.Selling.Float64()
+	}
+	bySelling := func(a, b models.HistoricalRate) int { return a.Selling.Cmp(b.Selling) }
+	d.WeekHigh = slices.MaxFunc(rates, bySelling).Selling
+	d.WeekLow = slices.MinFunc(rates, bySelling).Selling
+	d.Sparkline = sparkline(values)
+
+	return d
+}
+
+func percentChange(from, to decimal.Decimal) decimal.Decimal {
+	change, err := decimal.MulDiv(to.Sub(from), decimal.FromInt(100), from, decimal.RoundHalfEven)
+	if err != nil {
+		return decimal.Decimal{}
+	}
+	return change
+}
+
+// sparkline draws values as block characters scaled between their minimum
+// and maximum. Floats are precise enough for eight bar heights.
+func sparkline(values []float64) string {
+	low, high := slices.Min(values), slices.Max(values)
+
+	var b strings.Builder
+	for _, v := range values {
+		i := len(sparkBars) / 2
+		if high > low {
+			i = int(math.Round((v - low) / (high - low) * float64(len(sparkBars)-1)))
+		}
+		b.WriteRune(sparkBars[i])
+	}
+	return b.String()
+}
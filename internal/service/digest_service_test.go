@@ -0,0 +1,139 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
+	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	"github.com/akyTheDev/currency-bot/internal/models"
+)
+
+type fakeHistoryRepo struct {
+	history  []models.HistoricalRate
+	recorded []models.HistoricalRate
+	from, to time.Time
+	err      error
+}
+
+func (f *fakeHistoryRepo) Record(rates []models.HistoricalRate) error {
+	f.recorded = append(f.recorded, rates...)
+	return f.err
+}
+
+func (f *fakeHistoryRepo) GetHistory(from, to time.Time) ([]models.HistoricalRate, error) {
+	f.from, f.to = from, to
+	return f.history, f.err
+}
+
+func date(day int) time.Time {
+	return time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDay(t *testing.T) {
+	// 22:30 UTC is already the next day in Istanbul.
+	if got := Day(time.Date(2025, 1, 5, 22, 30, 0, 0, time.UTC)); !got.Equal(date(6)) {
+		t.Errorf("Day = %v; want %v", got, date(6))
+	}
+}
+
+func TestDigestDue(t *testing.T) {
+	monday, tuesday := date(6), date(7)
+	if !DigestDue(DigestDaily, tuesday) || !DigestDue(DigestWeekly, monday) || DigestDue(DigestWeekly, tuesday) || DigestDue("", monday) {
+		t.Error("DigestDue returned an unexpected schedule")
+	}
+}
+
+func TestDigestServiceRecord(t *testing.T) {
+	repo := &fakeHistoryRepo{}
+	s := NewDigestService(repo, logger)
+
+	s.Record([]fetcher.Rate{{
+		Code: "USD", Unit: 1, Buying: decimal.FromInt(35), Selling: decimal.MustParse("35.2"),
+		BanknoteBuying: decimal.MustParse("34.9"), BanknoteSelling: decimal.MustParse("35.3"),
+	}}, time.Date(2025, 1, 5, 22, 30, 0, 0, time.UTC))
+
+	if len(repo.recorded) != 1 || repo.recorded[0].Code != "USD" || !repo.recorded[0].Date.Equal(date(6)) || repo.recorded[0].Selling.String() != "35.2" || repo.recorded[0].BanknoteSelling.String() != "35.3" {
+		t.Errorf("recorded = %+v", repo.recorded)
+	}
+
+	// A Friday bulletin still served on Sunday is recorded for Friday.
+	repo.recorded = nil
+	s.Record([]fetcher.Rate{{Code: "USD", Unit: 1, Date: date(3)}}, time.Date(2025, 1, 5, 12, 0, 0, 0, time.UTC))
+	if len(repo.recorded) != 1 || !repo.recorded[0].Date.Equal(date(3)) {
+		t.Errorf("recorded = %+v; want the bulletin date", repo.recorded)
+	}
+}
+
+func TestDigestServiceDigest(t *testing.T) {
+	var history []models.HistoricalRate
+	// USD rises by 1 every day from the 1st to the 14th; EUR has one day.
+	for day := 1; day <= 14; day++ {
+		history = append(history, models.HistoricalRate{Code: "USD", Date: date(day), Selling: decimal.FromInt(int64(30 + day))})
+	}
+	history = append(history, models.HistoricalRate{Code: "EUR", Date: date(14), Selling: decimal.FromInt(36)})
+
+	repo := &fakeHistoryRepo{history: history}
+	s := NewDigestService(repo, logger)
+
+	digests, err := s.Digest([]currency.Code{"EUR", "GBP", "USD"}, date(15), fetcher.PriceForex)
+	if err != nil {
+		t.Fatalf("Digest: %v", err)
+	}
+	if !repo.from.Equal(date(15).AddDate(0, 0, -digestLookback)) || !repo.to.Equal(date(14)) {
+		t.Errorf("history window = %v..%v", repo.from, repo.to)
+	}
+	if len(digests) != 2 || digests[0].Code != "EUR" || digests[1].Code != "USD" {
+		t.Fatalf("digests = %+v; want EUR and USD", digests)
+	}
+
+	eur := digests[0]
+	if !eur.Close.Equal(decimal.FromInt(36)) || eur.HasDayChange || eur.HasWeekChange || eur.Sparkline != "▅" {
+		t.Errorf("EUR digest = %+v", eur)
+	}
+
+	usd := digests[1]
+	if !usd.Close.Equal(decimal.FromInt(44)) || !usd.CloseDate.Equal(date(14)) {
+		t.Errorf("USD close = %v on %v", usd.Close, usd.CloseDate)
+	}
+	// 1/43 and 7/37 rounded half to even at six places.
+	if !usd.HasDayChange || usd.DayChange.String() != "2.325581" {
+		t.Errorf("USD day change = %v", usd.DayChange)
+	}
+	if !usd.HasWeekChange || usd.WeekChange.String() != "18.918919" {
+		t.Errorf("USD week change = %v", usd.WeekChange)
+	}
+	if !usd.WeekHigh.Equal(decimal.FromInt(44)) || !usd.WeekLow.Equal(decimal.FromInt(38)) || usd.Sparkline != "▁▂▃▅▆▇█" {
+		t.Errorf("USD week = %v..%v %s", usd.WeekLow, usd.WeekHigh, usd.Sparkline)
+	}
+}
+
+func TestDigestServiceDigestBanknote(t *testing.T) {
+	// Banknote prices were recorded from the 2nd on; the 1st keeps forex.
+	history := []models.HistoricalRate{{Code: "USD", Date: date(1), Selling: decimal.FromInt(30)}}
+	for day := 2; day <= 3; day++ {
+		history = append(history, models.HistoricalRate{
+			Code: "USD", Date: date(day), Buying: decimal.FromInt(30), Selling: decimal.FromInt(31),
+			BanknoteBuying: decimal.FromInt(29), BanknoteSelling: decimal.FromInt(int64(31 + day)),
+		})
+	}
+	s := NewDigestService(&fakeHistoryRepo{history: history}, logger)
+
+	digests, err := s.Digest([]currency.Code{"USD"}, date(4), fetcher.PriceBanknote)
+	if err != nil || len(digests) != 1 {
+		t.Fatalf("Digest = %+v, %v", digests, err)
+	}
+	if usd := digests[0]; !usd.Close.Equal(decimal.FromInt(34)) || !usd.WeekLow.Equal(decimal.FromInt(30)) || usd.DayChange.String() != "3.030303" {
+		t.Errorf("USD banknote digest = %+v", usd)
+	}
+}
+
+func TestDigestServiceDigestError(t *testing.T) {
+	s := NewDigestService(&fakeHistoryRepo{err: errors.New("db down")}, logger)
+	if _, err := s.Digest([]currency.Code{"USD"}, date(15), fetcher.PriceForex); !errors.Is(err, domain.ErrGeneric) {
+		t.Errorf("error = %v; want %v", err, domain.ErrGeneric)
+	}
+}
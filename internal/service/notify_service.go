@@ -2,7 +2,9 @@ package service
 
 import (
 	"log"
+	"slices"
 
+	"github.com/akyTheDev/currency-bot/internal/currency"
 	"github.com/akyTheDev/currency-bot/internal/domain"
 	"github.com/akyTheDev/currency-bot/internal/fetcher"
 	"github.com/akyTheDev/currency-bot/internal/repository"
@@ -30,9 +32,9 @@ func (ns *NotifyService) GetUsersAndCurrencyRate() ([]int64, *fetcher.Rate, erro
 		return nil, rate, domain.ErrGeneric
 	}
 
-	users, err := ns.userRepository.GetAllUsers()
+	users, err := ns.userRepository.GetActiveUsers()
 	if err != nil {
-		ns.logger.Printf("NotifyService: GetUsersAndCurrencyRate: GetAllUsers %v\n", err)
+		ns.logger.Printf("NotifyService: GetUsersAndCurrencyRate: GetActiveUsers %v\n", err)
 		return nil, rate, domain.ErrGeneric
 	}
 
@@ -48,3 +50,49 @@ func (ns *NotifyService) GetUsersAndCurrencyRate() ([]int64, *fetcher.Rate, erro
 
 	return ids, rate, nil
 }
+
+func (ns *NotifyService) CurrentRate() (*fetcher.Rate, error) {
+	rate, err := ns.rateFetch.FetchRate()
+	if err != nil {
+		ns.logger.Printf("NotifyService: CurrentRate: FetchRate %v\n", err)
+		return nil, domain.ErrGeneric
+	}
+	return rate, nil
+}
+
+// Bulletin returns every rate of the current bulletin, or just the default
+// rate when the fetcher can't provide a bulletin.
+func (ns *NotifyService) Bulletin() ([]fetcher.Rate, error) {
+	if bf, ok := ns.rateFetch.(fetcher.BulletinFetcher); ok {
+		rates, err := bf.FetchBulletin()
+		if err != nil {
+			ns.logger.Printf("NotifyService: Bulletin: FetchBulletin %v\n", err)
+			return nil, domain.ErrGeneric
+		}
+		return rates, nil
+	}
+
+	rate, err := ns.CurrentRate()
+	if err != nil {
+		return nil, err
+	}
+	return []fetcher.Rate{*rate}, nil
+}
+
+// Rates returns the current rates for the given currency codes, in order.
+func (ns *NotifyService) Rates(codes []currency.Code) ([]fetcher.Rate, error) {
+	bulletin, err := ns.Bulletin()
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]fetcher.Rate, 0, len(codes))
+	for _, code := range codes {
+		i := slices.IndexFunc(bulletin, func(r fetcher.Rate) bool { return r.Code == code })
+		if i < 0 {
+			return nil, domain.ErrCurrencyNotFound
+		}
+		rates = append(rates, bulletin[i])
+	}
+	return rates, nil
+}
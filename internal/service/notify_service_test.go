@@ -6,6 +6,8 @@ import (
 	"os"
 	"testing"
 
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/decimal"
 	"github.com/akyTheDev/currency-bot/internal/domain"
 	"github.com/akyTheDev/currency-bot/internal/fetcher"
 	"github.com/akyTheDev/currency-bot/internal/models"
@@ -16,9 +18,16 @@ type fakeUserRepoNotifyService struct {
 	err   error
 }
 
-func (f *fakeUserRepoNotifyService) CreateUser(chatID int64) error       { return nil }
-func (f *fakeUserRepoNotifyService) DeleteUser(chatID int64) error       { return nil }
-func (f *fakeUserRepoNotifyService) GetAllUsers() ([]models.User, error) { return f.users, f.err }
+func (f *fakeUserRepoNotifyService) CreateUser(chatID int64, actor string) error { return nil }
+func (f *fakeUserRepoNotifyService) DeleteUser(chatID int64, actor string) error { return nil }
+func (f *fakeUserRepoNotifyService) GetAllUsers() ([]models.User, error)         { return f.users, f.err }
+func (f *fakeUserRepoNotifyService) GetActiveUsers() ([]models.User, error) {
+	return f.users, f.err
+}
+func (f *fakeUserRepoNotifyService) GetUser(chatID int64) (*models.User, error)  { return nil, nil }
+func (f *fakeUserRepoNotifyService) CountUsers() (int, error)                    { return len(f.users), f.err }
+func (f *fakeUserRepoNotifyService) CountActiveUsers() (int, error)              { return len(f.users), f.err }
+func (f *fakeUserRepoNotifyService) BlockUser(chatID int64, reason string) error { return nil }
 
 type fakeRateFetcher struct {
 	rate *fetcher.Rate
@@ -55,27 +64,27 @@ func TestGetUsersAndCurrencyRate(t *testing.T) {
 		},
 		{
 			name:        "GetAllUsersError",
-			fetcherRate: &fetcher.Rate{Selling: 25.5, Buying: 24.5},
+			fetcherRate: &fetcher.Rate{Selling: decimal.MustParse("25.5"), Buying: decimal.MustParse("24.5")},
 			fetcherErr:  nil,
 			repoUsers:   nil,
 			repoErr:     errors.New("db failed"),
 			wantIDs:     nil,
-			wantRate:    &fetcher.Rate{Selling: 25.5, Buying: 24.5},
+			wantRate:    &fetcher.Rate{Selling: decimal.MustParse("25.5"), Buying: decimal.MustParse("24.5")},
 			wantErr:     domain.ErrGeneric,
 		},
 		{
 			name:        "NoSubscribers",
-			fetcherRate: &fetcher.Rate{Selling: 25.5, Buying: 24.5},
+			fetcherRate: &fetcher.Rate{Selling: decimal.MustParse("25.5"), Buying: decimal.MustParse("24.5")},
 			fetcherErr:  nil,
 			repoUsers:   []models.User{},
 			repoErr:     nil,
 			wantIDs:     nil,
-			wantRate:    &fetcher.Rate{Selling: 25.5, Buying: 24.5},
+			wantRate:    &fetcher.Rate{Selling: decimal.MustParse("25.5"), Buying: decimal.MustParse("24.5")},
 			wantErr:     nil,
 		},
 		{
 			name:        "SomeSubscribers",
-			fetcherRate: &fetcher.Rate{Selling: 26.5, Buying: 24.5},
+			fetcherRate: &fetcher.Rate{Selling: decimal.MustParse("26.5"), Buying: decimal.MustParse("24.5")},
 			fetcherErr:  nil,
 			repoUsers: []models.User{
 				{ChatID: 101},
@@ -84,7 +93,7 @@ func TestGetUsersAndCurrencyRate(t *testing.T) {
 			},
 			repoErr:  nil,
 			wantIDs:  []int64{101, 202, 303},
-			wantRate: &fetcher.Rate{Selling: 26.5, Buying: 24.5},
+			wantRate: &fetcher.Rate{Selling: decimal.MustParse("26.5"), Buying: decimal.MustParse("24.5")},
 			wantErr:  nil,
 		},
 		// },
@@ -141,3 +150,113 @@ func TestGetUsersAndCurrencyRate(t *testing.T) {
 		})
 	}
 }
+
+func TestCurrentRate(t *testing.T) {
+	tests := []struct {
+		name        string
+		fetcherRate *fetcher.Rate
+		fetcherErr  error
+		wantRate    *fetcher.Rate
+		wantErr     error
+	}{
+		{
+			name:       "FetchRateError",
+			fetcherErr: errors.New("fetch failed"),
+			wantErr:    domain.ErrGeneric,
+		},
+		{
+			name:        "Success",
+			fetcherRate: &fetcher.Rate{Selling: decimal.MustParse("25.5"), Buying: decimal.MustParse("24.5")},
+			wantRate:    &fetcher.Rate{Selling: decimal.MustParse("25.5"), Buying: decimal.MustParse("24.5")},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ff := &fakeRateFetcher{rate: tc.fetcherRate, err: tc.fetcherErr}
+			ns := NewNotifyService(logger, &fakeUserRepoNotifyService{}, ff)
+
+			rate, err := ns.CurrentRate()
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error: %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantRate == nil {
+				if rate != nil {
+					t.Errorf("expected nil rate, got %v", rate)
+				}
+			} else if rate == nil || *rate != *tc.wantRate {
+				t.Errorf("expected rate %v, got %v", tc.wantRate, rate)
+			}
+		})
+	}
+}
+
+type fakeBulletinFetcher struct {
+	fakeRateFetcher
+	rates []fetcher.Rate
+}
+
+func (f *fakeBulletinFetcher) FetchBulletin() ([]fetcher.Rate, error) {
+	return f.rates, f.err
+}
+
+func TestRates(t *testing.T) {
+	bulletin := []fetcher.Rate{
+		{Code: "USD", Selling: decimal.MustParse("34.2")},
+		{Code: "EUR", Selling: decimal.MustParse("36.1")},
+	}
+
+	tests := []struct {
+		name      string
+		fetcher   fetcher.RateFetcher
+		codes     []currency.Code
+		wantCodes []currency.Code
+		wantErr   error
+	}{
+		{
+			name:      "FromBulletin",
+			fetcher:   &fakeBulletinFetcher{rates: bulletin},
+			codes:     []currency.Code{"EUR", "USD"},
+			wantCodes: []currency.Code{"EUR", "USD"},
+		},
+		{
+			name:    "UnknownCode",
+			fetcher: &fakeBulletinFetcher{rates: bulletin},
+			codes:   []currency.Code{"XYZ"},
+			wantErr: domain.ErrCurrencyNotFound,
+		},
+		{
+			name:    "BulletinError",
+			fetcher: &fakeBulletinFetcher{fakeRateFetcher: fakeRateFetcher{err: errors.New("fetch failed")}},
+			codes:   []currency.Code{"EUR"},
+			wantErr: domain.ErrGeneric,
+		},
+		{
+			name:      "RateOnlyFetcher",
+			fetcher:   &fakeRateFetcher{rate: &fetcher.Rate{Code: "EUR", Selling: decimal.MustParse("36.1")}},
+			codes:     []currency.Code{"EUR"},
+			wantCodes: []currency.Code{"EUR"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ns := NewNotifyService(logger, &fakeUserRepoNotifyService{}, tc.fetcher)
+
+			rates, err := ns.Rates(tc.codes)
+
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("expected error: %v, got %v", tc.wantErr, err)
+			}
+			if len(rates) != len(tc.wantCodes) {
+				t.Fatalf("got %d rates, want %d", len(rates), len(tc.wantCodes))
+			}
+			for i, code := range tc.wantCodes {
+				if rates[i].Code != code {
+					t.Errorf("rates[%d].Code = %q; want %q", i, rates[i].Code, code)
+				}
+			}
+		})
+	}
+}
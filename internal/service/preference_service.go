@@ -0,0 +1,152 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	"github.com/akyTheDev/currency-bot/internal/repository"
+)
+
+type PreferenceService struct {
+	prefRepo repository.PreferenceRepository
+	logger   *log.Logger
+}
+
+func NewPreferenceService(prefRepo repository.PreferenceRepository, logger *log.Logger) *PreferenceService {
+	return &PreferenceService{prefRepo: prefRepo, logger: logger}
+}
+
+// Language returns the language stored for a chat, or "" when none is stored
+// or it can't be loaded.
+func (s *PreferenceService) Language(chatID int64) string {
+	prefs, err := s.prefRepo.GetPreferences(chatID)
+	if err != nil {
+		s.logger.Printf("ERROR: PreferenceService:Language: %v\n", err)
+		return ""
+	}
+	return prefs.Language
+}
+
+func (s *PreferenceService) Languages() map[int64]string {
+	languages, err := s.prefRepo.GetLanguages()
+	if err != nil {
+		s.logger.Printf("ERROR: PreferenceService:Languages: %v\n", err)
+		return nil
+	}
+	return languages
+}
+
+func (s *PreferenceService) SetLanguage(chatID int64, language string) error {
+	if err := s.prefRepo.SetLanguage(chatID, language); err != nil {
+		s.logger.Printf("ERROR: PreferenceService:SetLanguage: %v\n", err)
+		return domain.ErrGeneric
+	}
+	return nil
+}
+
+// InitLanguage remembers the language a chat was first seen with, without
+// overriding an explicit choice.
+func (s *PreferenceService) InitLanguage(chatID int64, language string) {
+	if err := s.prefRepo.InitLanguage(chatID, language); err != nil {
+		s.logger.Printf("ERROR: PreferenceService:InitLanguage: %v\n", err)
+	}
+}
+
+// LiveMessage returns the chat's live message id, or 0 when live mode is off
+// or it can't be loaded.
+func (s *PreferenceService) LiveMessage(chatID int64) int {
+	prefs, err := s.prefRepo.GetPreferences(chatID)
+	if err != nil {
+		s.logger.Printf("ERROR: PreferenceService:LiveMessage: %v\n", err)
+		return 0
+	}
+	return prefs.LiveMessageID
+}
+
+func (s *PreferenceService) LiveMessages() map[int64]int {
+	messages, err := s.prefRepo.GetLiveMessages()
+	if err != nil {
+		s.logger.Printf("ERROR: PreferenceService:LiveMessages: %v\n", err)
+		return nil
+	}
+	return messages
+}
+
+func (s *PreferenceService) SetLiveMessage(chatID int64, messageID int) error {
+	if err := s.prefRepo.SetLiveMessage(chatID, messageID); err != nil {
+		s.logger.Printf("ERROR: PreferenceService:SetLiveMessage: %v\n", err)
+		return domain.ErrGeneric
+	}
+	return nil
+}
+
+// Digest returns the chat's digest kind, or "" when it gets none or it can't
+// be loaded.
+func (s *PreferenceService) Digest(chatID int64) string {
+	prefs, err := s.prefRepo.GetPreferences(chatID)
+	if err != nil {
+		s.logger.Printf("ERROR: PreferenceService:Digest: %v\n", err)
+		return ""
+	}
+	return prefs.Digest
+}
+
+func (s *PreferenceService) SetDigest(chatID int64, digest string) error {
+	if err := s.prefRepo.SetDigest(chatID, digest); err != nil {
+		s.logger.Printf("ERROR: PreferenceService:SetDigest: %v\n", err)
+		return domain.ErrGeneric
+	}
+	return nil
+}
+
+// PendingDigests returns the digest kind of every chat not yet sent a digest
+// on day.
+func (s *PreferenceService) PendingDigests(day time.Time) map[int64]string {
+	digests, err := s.prefRepo.GetPendingDigests(day)
+	if err != nil {
+		s.logger.Printf("ERROR: PreferenceService:PendingDigests: %v\n", err)
+		return nil
+	}
+	return digests
+}
+
+func (s *PreferenceService) MarkDigestSent(chatID int64, day time.Time) {
+	if err := s.prefRepo.MarkDigestSent(chatID, day); err != nil {
+		s.logger.Printf("ERROR: PreferenceService:MarkDigestSent: %v\n", err)
+	}
+}
+
+// PriceType returns the chat's price type, defaulting to forex when none is
+// stored or it can't be loaded.
+func (s *PreferenceService) PriceType(chatID int64) string {
+	prefs, err := s.prefRepo.GetPreferences(chatID)
+	if err != nil {
+		s.logger.Printf("ERROR: PreferenceService:PriceType: %v\n", err)
+		return fetcher.PriceForex
+	}
+	if prefs.PriceType == "" {
+		return fetcher.PriceForex
+	}
+	return prefs.PriceType
+}
+
+// PriceTypes returns the price type of the chats that chose one; missing
+// chats use forex prices.
+func (s *PreferenceService) PriceTypes() map[int64]string {
+	priceTypes, err := s.prefRepo.GetPriceTypes()
+	if err != nil {
+		s.logger.Printf("ERROR: PreferenceService:PriceTypes: %v\n", err)
+		return nil
+	}
+	return priceTypes
+}
+
+func (s *PreferenceService) SetPriceType(chatID int64, priceType string) error {
+	if err := s.prefRepo.SetPriceType(chatID, priceType); err != nil {
+		s.logger.Printf("ERROR: PreferenceService:SetPriceType: %v\n", err)
+		return domain.ErrGeneric
+	}
+	return nil
+}
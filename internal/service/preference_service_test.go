@@ -0,0 +1,202 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/fetcher"
+	"github.com/akyTheDev/currency-bot/internal/models"
+)
+
+type fakePreferenceRepo struct {
+	prefs     *models.Preferences
+	getErr    error
+	languages map[int64]string
+	setErr    error
+	set       map[int64]string
+	live      map[int64]int
+	digests   map[int64]string
+	sent      map[int64]time.Time
+	prices    map[int64]string
+}
+
+func (f *fakePreferenceRepo) GetPreferences(chatID int64) (*models.Preferences, error) {
+	return f.prefs, f.getErr
+}
+
+func (f *fakePreferenceRepo) GetLanguages() (map[int64]string, error) {
+	return f.languages, f.getErr
+}
+
+func (f *fakePreferenceRepo) SetLanguage(chatID int64, language string) error {
+	if f.set == nil {
+		f.set = make(map[int64]string)
+	}
+	f.set[chatID] = language
+	return f.setErr
+}
+
+func (f *fakePreferenceRepo) InitLanguage(chatID int64, language string) error {
+	return f.SetLanguage(chatID, language)
+}
+
+func (f *fakePreferenceRepo) GetLiveMessages() (map[int64]int, error) {
+	return f.live, f.getErr
+}
+
+func (f *fakePreferenceRepo) SetLiveMessage(chatID int64, messageID int) error {
+	if f.live == nil {
+		f.live = make(map[int64]int)
+	}
+	if messageID == 0 {
+		delete(f.live, chatID)
+	} else {
+		f.live[chatID] = messageID
+	}
+	return f.setErr
+}
+
+func (f *fakePreferenceRepo) SetDigest(chatID int64, digest string) error {
+	if f.digests == nil {
+		f.digests = make(map[int64]string)
+	}
+	f.digests[chatID] = digest
+	return f.setErr
+}
+
+func (f *fakePreferenceRepo) GetPendingDigests(day time.Time) (map[int64]string, error) {
+	pending := make(map[int64]string)
+	for chatID, digest := range f.digests {
+		if digest != "" && f.sent[chatID].Before(day) {
+			pending[chatID] = digest
+		}
+	}
+	return pending, f.getErr
+}
+
+func (f *fakePreferenceRepo) MarkDigestSent(chatID int64, day time.Time) error {
+	if f.sent == nil {
+		f.sent = make(map[int64]time.Time)
+	}
+	f.sent[chatID] = day
+	return f.setErr
+}
+
+func (f *fakePreferenceRepo) GetPriceTypes() (map[int64]string, error) {
+	return f.prices, f.getErr
+}
+
+func (f *fakePreferenceRepo) SetPriceType(chatID int64, priceType string) error {
+	if f.prices == nil {
+		f.prices = make(map[int64]string)
+	}
+	f.prices[chatID] = priceType
+	return f.setErr
+}
+
+func TestPreferenceServiceLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		repo     *fakePreferenceRepo
+		expected string
+	}{
+		{name: "Stored", repo: &fakePreferenceRepo{prefs: &models.Preferences{ChatID: 1, Language: "tr"}}, expected: "tr"},
+		{name: "Error", repo: &fakePreferenceRepo{getErr: errors.New("db down")}, expected: ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := NewPreferenceService(tc.repo, logger)
+			if got := s.Language(1); got != tc.expected {
+				t.Errorf("Language = %q; want %q", got, tc.expected)
+			}
+		})
+	}
+}
+
+func TestPreferenceServiceSetLanguage(t *testing.T) {
+	repo := &fakePreferenceRepo{}
+	s := NewPreferenceService(repo, logger)
+
+	if err := s.SetLanguage(1, "tr"); err != nil {
+		t.Fatalf("SetLanguage: unexpected error %v", err)
+	}
+	if repo.set[1] != "tr" {
+		t.Errorf("stored language = %q; want %q", repo.set[1], "tr")
+	}
+
+	repo.setErr = errors.New("db down")
+	if err := s.SetLanguage(1, "en"); !errors.Is(err, domain.ErrGeneric) {
+		t.Errorf("SetLanguage error = %v; want %v", err, domain.ErrGeneric)
+	}
+}
+
+func TestPreferenceServiceLiveMessage(t *testing.T) {
+	repo := &fakePreferenceRepo{prefs: &models.Preferences{ChatID: 1, LiveMessageID: 77}}
+	s := NewPreferenceService(repo, logger)
+
+	if got := s.LiveMessage(1); got != 77 {
+		t.Errorf("LiveMessage = %d; want 77", got)
+	}
+
+	if err := s.SetLiveMessage(2, 88); err != nil {
+		t.Fatalf("SetLiveMessage: unexpected error %v", err)
+	}
+	if got := s.LiveMessages(); len(got) != 1 || got[2] != 88 {
+		t.Errorf("LiveMessages = %v; want map[2:88]", got)
+	}
+
+	repo.setErr = errors.New("db down")
+	if err := s.SetLiveMessage(2, 0); !errors.Is(err, domain.ErrGeneric) {
+		t.Errorf("SetLiveMessage error = %v; want %v", err, domain.ErrGeneric)
+	}
+}
+
+func TestPreferenceServiceDigests(t *testing.T) {
+	repo := &fakePreferenceRepo{}
+	s := NewPreferenceService(repo, logger)
+	day := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	if err := s.SetDigest(1, DigestDaily); err != nil {
+		t.Fatalf("SetDigest: unexpected error %v", err)
+	}
+	s.SetDigest(2, "")
+
+	if got := s.PendingDigests(day); len(got) != 1 || got[1] != DigestDaily {
+		t.Errorf("PendingDigests = %v; want map[1:daily]", got)
+	}
+	s.MarkDigestSent(1, day)
+	if got := s.PendingDigests(day); len(got) != 0 {
+		t.Errorf("PendingDigests after sending = %v; want none", got)
+	}
+	if got := s.PendingDigests(day.AddDate(0, 0, 1)); len(got) != 1 {
+		t.Errorf("PendingDigests the next day = %v; want chat 1 again", got)
+	}
+}
+
+func TestPreferenceServicePriceType(t *testing.T) {
+	repo := &fakePreferenceRepo{prefs: &models.Preferences{ChatID: 1}}
+	s := NewPreferenceService(repo, logger)
+
+	if got := s.PriceType(1); got != fetcher.PriceForex {
+		t.Errorf("PriceType without a choice = %q; want %q", got, fetcher.PriceForex)
+	}
+	repo.prefs.PriceType = fetcher.PriceBanknote
+	if got := s.PriceType(1); got != fetcher.PriceBanknote {
+		t.Errorf("PriceType = %q; want %q", got, fetcher.PriceBanknote)
+	}
+
+	if err := s.SetPriceType(2, fetcher.PriceBanknote); err != nil {
+		t.Fatalf("SetPriceType: unexpected error %v", err)
+	}
+	if got := s.PriceTypes(); len(got) != 1 || got[2] != fetcher.PriceBanknote {
+		t.Errorf("PriceTypes = %v; want map[2:banknote]", got)
+	}
+
+	repo.setErr = errors.New("db down")
+	if err := s.SetPriceType(2, fetcher.PriceForex); !errors.Is(err, domain.ErrGeneric) {
+		t.Errorf("SetPriceType error = %v; want %v", err, domain.ErrGeneric)
+	}
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"log"
+	"slices"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/models"
+	"github.com/akyTheDev/currency-bot/internal/repository"
+)
+
+type SubscriptionService struct {
+	subRepo repository.SubscriptionRepository
+	logger  *log.Logger
+}
+
+func NewSubscriptionService(subRepo repository.SubscriptionRepository, logger *log.Logger) *SubscriptionService {
+	return &SubscriptionService{subRepo: subRepo, logger: logger}
+}
+
+func (s *SubscriptionService) Currencies(chatID int64) ([]currency.Code, error) {
+	currencies, err := s.subRepo.GetSubscriptions(chatID)
+	if err != nil {
+		s.logger.Printf("ERROR: SubscriptionService:Currencies: %v\n", err)
+		return nil, domain.ErrGeneric
+	}
+	return currencies, nil
+}
+
+// All returns every chat's subscribed currencies, or nil if they can't be
+// loaded.
+func (s *SubscriptionService) All() map[int64][]currency.Code {
+	subscriptions, err := s.subRepo.GetAllSubscriptions()
+	if err != nil {
+		s.logger.Printf("ERROR: SubscriptionService:All: %v\n", err)
+		return nil
+	}
+	return subscriptions
+}
+
+// Toggle subscribes the chat to code, or unsubscribes it if it already
+// was, on behalf of userID, and reports whether the chat is subscribed
+// afterwards.
+func (s *SubscriptionService) Toggle(chatID int64, code currency.Code, userID int64) (bool, error) {
+	current, err := s.Currencies(chatID)
+	if err != nil {
+		return false, err
+	}
+
+	actor := models.UserActor(userID)
+	subscribe := !slices.Contains(current, code)
+	if subscribe {
+		err = s.subRepo.Subscribe(chatID, code, actor)
+	} else {
+		err = s.subRepo.Unsubscribe(chatID, code, actor)
+	}
+	if err != nil {
+		s.logger.Printf("ERROR: SubscriptionService:Toggle: %v\n", err)
+		return false, domain.ErrGeneric
+	}
+
+	return subscribe, nil
+}
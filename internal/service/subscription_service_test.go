@@ -0,0 +1,69 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/akyTheDev/currency-bot/internal/currency"
+	"github.com/akyTheDev/currency-bot/internal/domain"
+)
+
+type fakeSubscriptionRepo struct {
+	subscriptions map[int64][]currency.Code
+	err           error
+	lastActor     string
+}
+
+func (f *fakeSubscriptionRepo) GetSubscriptions(chatID int64) ([]currency.Code, error) {
+	return f.subscriptions[chatID], f.err
+}
+
+func (f *fakeSubscriptionRepo) GetAllSubscriptions() (map[int64][]currency.Code, error) {
+	return f.subscriptions, f.err
+}
+
+func (f *fakeSubscriptionRepo) Subscribe(chatID int64, code currency.Code, actor string) error {
+	f.lastActor = actor
+	f.subscriptions[chatID] = append(f.subscriptions[chatID], code)
+	return nil
+}
+
+func (f *fakeSubscriptionRepo) Unsubscribe(chatID int64, code currency.Code, actor string) error {
+	f.lastActor = actor
+	f.subscriptions[chatID] = slices.DeleteFunc(f.subscriptions[chatID], func(c currency.Code) bool { return c == code })
+	return nil
+}
+
+func TestSubscriptionServiceToggle(t *testing.T) {
+	repo := &fakeSubscriptionRepo{subscriptions: map[int64][]currency.Code{1: {"EUR"}}}
+	s := NewSubscriptionService(repo, logger)
+
+	subscribed, err := s.Toggle(1, "USD", 7)
+	if err != nil || !subscribed {
+		t.Fatalf("Toggle(USD) = %v, %v; want true, nil", subscribed, err)
+	}
+	if repo.lastActor != "user:7" {
+		t.Errorf("actor = %q; want %q", repo.lastActor, "user:7")
+	}
+
+	subscribed, err = s.Toggle(1, "EUR", 7)
+	if err != nil || subscribed {
+		t.Fatalf("Toggle(EUR) = %v, %v; want false, nil", subscribed, err)
+	}
+
+	if got := repo.subscriptions[1]; len(got) != 1 || got[0] != "USD" {
+		t.Errorf("subscriptions = %v; want [USD]", got)
+	}
+}
+
+func TestSubscriptionServiceErrors(t *testing.T) {
+	s := NewSubscriptionService(&fakeSubscriptionRepo{err: errors.New("db down")}, logger)
+
+	if _, err := s.Toggle(1, "USD", 7); !errors.Is(err, domain.ErrGeneric) {
+		t.Errorf("Toggle error = %v; want %v", err, domain.ErrGeneric)
+	}
+	if all := s.All(); all != nil {
+		t.Errorf("All = %v; want nil", all)
+	}
+}
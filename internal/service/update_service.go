@@ -0,0 +1,70 @@
+package service
+
+import (
+	"log"
+	"time"
+
+	"github.com/akyTheDev/currency-bot/internal/repository"
+)
+
+// ProcessedUpdatesWindow is how long handled update IDs are remembered.
+// Telegram keeps undelivered updates for 24 hours, so a redelivered update
+// is always younger than that.
+const ProcessedUpdatesWindow = 24 * time.Hour
+
+type UpdateService struct {
+	updateRepo repository.UpdateRepository
+	logger     *log.Logger
+	now        func() time.Time
+}
+
+func NewUpdateService(updateRepo repository.UpdateRepository, logger *log.Logger) *UpdateService {
+	return &UpdateService{updateRepo: updateRepo, logger: logger, now: time.Now}
+}
+
+// Offset returns the last handled update_id, or 0 to let Telegram decide
+// when it can't be loaded.
+func (s *UpdateService) Offset() int {
+	offset, err := s.updateRepo.GetOffset()
+	if err != nil {
+		s.logger.Printf("ERROR: UpdateService:Offset: %v\n", err)
+		return 0
+	}
+	return offset
+}
+
+func (s *UpdateService) SaveOffset(updateID int) {
+	if err := s.updateRepo.SaveOffset(updateID); err != nil {
+		s.logger.Printf("ERROR: UpdateService:SaveOffset: %v\n", err)
+	}
+}
+
+// ProcessedAfter returns the handled update IDs above offset, the ones
+// Telegram may deliver again. Lookup errors return none so updates are
+// handled rather than lost.
+func (s *UpdateService) ProcessedAfter(offset int) []int {
+	ids, err := s.updateRepo.GetProcessedAfter(offset)
+	if err != nil {
+		s.logger.Printf("ERROR: UpdateService:ProcessedAfter: %v\n", err)
+		return nil
+	}
+	return ids
+}
+
+func (s *UpdateService) MarkProcessed(updateID int) {
+	if err := s.updateRepo.MarkProcessed(updateID); err != nil {
+		s.logger.Printf("ERROR: UpdateService:MarkProcessed: %v\n", err)
+	}
+}
+
+// Prune forgets update IDs handled more than ProcessedUpdatesWindow ago.
+func (s *UpdateService) Prune() {
+	removed, err := s.updateRepo.PruneProcessed(s.now().Add(-ProcessedUpdatesWindow))
+	if err != nil {
+		s.logger.Printf("ERROR: UpdateService:Prune: %v\n", err)
+		return
+	}
+	if removed > 0 {
+		s.logger.Printf("Pruned %d processed update ids\n", removed)
+	}
+}
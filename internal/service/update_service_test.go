@@ -0,0 +1,91 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeUpdateRepo struct {
+	offset    int
+	processed map[int]bool
+	err       error
+	prunedAt  time.Time
+}
+
+func (f *fakeUpdateRepo) GetOffset() (int, error) {
+	return f.offset, f.err
+}
+
+func (f *fakeUpdateRepo) SaveOffset(updateID int) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.offset = max(f.offset, updateID)
+	return nil
+}
+
+func (f *fakeUpdateRepo) GetProcessedAfter(updateID int) ([]int, error) {
+	var ids []int
+	for id := range f.processed {
+		if id > updateID {
+			ids = append(ids, id)
+		}
+	}
+	return ids, f.err
+}
+
+func (f *fakeUpdateRepo) MarkProcessed(updateID int) error {
+	if f.processed == nil {
+		f.processed = make(map[int]bool)
+	}
+	f.processed[updateID] = true
+	return f.err
+}
+
+func (f *fakeUpdateRepo) PruneProcessed(before time.Time) (int64, error) {
+	f.prunedAt = before
+	return 0, f.err
+}
+
+func TestUpdateService(t *testing.T) {
+	repo := &fakeUpdateRepo{offset: 10}
+	s := NewUpdateService(repo, logger)
+
+	if got := s.Offset(); got != 10 {
+		t.Errorf("Offset = %d; want 10", got)
+	}
+	s.SaveOffset(12)
+	s.SaveOffset(11)
+	if got := s.Offset(); got != 12 {
+		t.Errorf("Offset after saves = %d; want 12", got)
+	}
+
+	if ids := s.ProcessedAfter(12); len(ids) != 0 {
+		t.Errorf("ProcessedAfter(12) = %v before marking", ids)
+	}
+	s.MarkProcessed(13)
+	s.MarkProcessed(9)
+	if ids := s.ProcessedAfter(12); len(ids) != 1 || ids[0] != 13 {
+		t.Errorf("ProcessedAfter(12) = %v; want [13]", ids)
+	}
+
+	now := time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	s.Prune()
+	if want := now.Add(-ProcessedUpdatesWindow); !repo.prunedAt.Equal(want) {
+		t.Errorf("pruned before %v; want %v", repo.prunedAt, want)
+	}
+}
+
+func TestUpdateServiceErrors(t *testing.T) {
+	repo := &fakeUpdateRepo{offset: 10, processed: map[int]bool{5: true}, err: errors.New("db down")}
+	s := NewUpdateService(repo, logger)
+
+	if got := s.Offset(); got != 0 {
+		t.Errorf("Offset = %d; want 0 on error", got)
+	}
+	if ids := s.ProcessedAfter(0); ids != nil {
+		t.Errorf("ProcessedAfter = %v on error; want none so updates are handled", ids)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"github.com/akyTheDev/currency-bot/internal/domain"
+	"github.com/akyTheDev/currency-bot/internal/models"
 	"github.com/akyTheDev/currency-bot/internal/repository"
 )
 
@@ -16,8 +17,11 @@ func NewUserService(userRepo repository.UserRepository, logger *log.Logger) *Use
 	return &UserService{userRepo: userRepo, logger: logger}
 }
 
-func (s *UserService) Register(chatID int64) error {
-	err := s.userRepo.CreateUser(chatID)
+// Register subscribes chatID to notifications on behalf of userID, who is
+// recorded as the actor; in groups and channels that is the admin, not the
+// chat.
+func (s *UserService) Register(chatID, userID int64) error {
+	err := s.userRepo.CreateUser(chatID, models.UserActor(userID))
 	if err != nil {
 		s.logger.Printf("ERROR: UserService:Register: %v\n", err)
 		if err == domain.ErrUserAlreadyExists {
@@ -28,10 +32,15 @@ func (s *UserService) Register(chatID int64) error {
 	return nil
 }
 
-func (s *UserService) Delete(chatID int64) error {
-	err := s.userRepo.DeleteUser(chatID)
+// Delete unregisters chatID on behalf of userID.
+func (s *UserService) Delete(chatID, userID int64) error {
+	return s.Remove(chatID, models.UserActor(userID))
+}
+
+func (s *UserService) Remove(chatID int64, actor string) error {
+	err := s.userRepo.DeleteUser(chatID, actor)
 	if err != nil {
-		s.logger.Printf("ERROR: UserService:Delete: %v\n", err)
+		s.logger.Printf("ERROR: UserService:Remove: %v\n", err)
 		if err == domain.ErrUserNotFound {
 			return err
 		}
@@ -39,3 +48,60 @@ func (s *UserService) Delete(chatID int64) error {
 	}
 	return nil
 }
+
+func (s *UserService) List() ([]models.User, error) {
+	users, err := s.userRepo.GetAllUsers()
+	if err != nil {
+		s.logger.Printf("ERROR: UserService:List: %v\n", err)
+		return nil, domain.ErrGeneric
+	}
+	return users, nil
+}
+
+func (s *UserService) ListActive() ([]models.User, error) {
+	users, err := s.userRepo.GetActiveUsers()
+	if err != nil {
+		s.logger.Printf("ERROR: UserService:ListActive: %v\n", err)
+		return nil, domain.ErrGeneric
+	}
+	return users, nil
+}
+
+func (s *UserService) Count() (int, error) {
+	count, err := s.userRepo.CountUsers()
+	if err != nil {
+		s.logger.Printf("ERROR: UserService:Count: %v\n", err)
+		return 0, domain.ErrGeneric
+	}
+	return count, nil
+}
+
+// CountActive counts the users a broadcast reaches.
+func (s *UserService) CountActive() (int, error) {
+	count, err := s.userRepo.CountActiveUsers()
+	if err != nil {
+		s.logger.Printf("ERROR: UserService:CountActive: %v\n", err)
+		return 0, domain.ErrGeneric
+	}
+	return count, nil
+}
+
+func (s *UserService) Get(chatID int64) (*models.User, error) {
+	user, err := s.userRepo.GetUser(chatID)
+	if err != nil {
+		s.logger.Printf("ERROR: UserService:Get: %v\n", err)
+		if err == domain.ErrUserNotFound {
+			return nil, err
+		}
+		return nil, domain.ErrGeneric
+	}
+	return user, nil
+}
+
+func (s *UserService) Deactivate(chatID int64, reason string) error {
+	if err := s.userRepo.BlockUser(chatID, reason); err != nil {
+		s.logger.Printf("ERROR: UserService:Deactivate: %v\n", err)
+		return domain.ErrGeneric
+	}
+	return nil
+}
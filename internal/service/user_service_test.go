@@ -11,23 +11,56 @@ import (
 )
 
 type fakeUserRepo struct {
-	createErr  error
-	deleteErr  error
-	lastChatId int64
+	lastActor   string
+	createErr   error
+	deleteErr   error
+	lastChatId  int64
+	users       []models.User
+	listErr     error
+	count       int
+	activeCount int
+	countErr    error
+	user        *models.User
+	getErr      error
+	blockErr    error
 }
 
-func (f *fakeUserRepo) CreateUser(chatID int64) error {
+func (f *fakeUserRepo) CreateUser(chatID int64, actor string) error {
 	f.lastChatId = chatID
+	f.lastActor = actor
 	return f.createErr
 }
 
-func (f *fakeUserRepo) DeleteUser(chatID int64) error {
+func (f *fakeUserRepo) DeleteUser(chatID int64, actor string) error {
 	f.lastChatId = chatID
+	f.lastActor = actor
 	return f.deleteErr
 }
 
 func (f *fakeUserRepo) GetAllUsers() ([]models.User, error) {
-	return nil, nil
+	return f.users, f.listErr
+}
+
+func (f *fakeUserRepo) GetActiveUsers() ([]models.User, error) {
+	return f.users, f.listErr
+}
+
+func (f *fakeUserRepo) GetUser(chatID int64) (*models.User, error) {
+	f.lastChatId = chatID
+	return f.user, f.getErr
+}
+
+func (f *fakeUserRepo) CountUsers() (int, error) {
+	return f.count, f.countErr
+}
+
+func (f *fakeUserRepo) CountActiveUsers() (int, error) {
+	return f.activeCount, f.countErr
+}
+
+func (f *fakeUserRepo) BlockUser(chatID int64, reason string) error {
+	f.lastChatId = chatID
+	return f.blockErr
 }
 
 func TestUserServiceRegister(t *testing.T) {
@@ -61,7 +94,11 @@ func TestUserServiceRegister(t *testing.T) {
 			}
 			u := NewUserService(f, log.New(os.Stdout, "", 0))
 
-			err := u.Register(12345)
+			err := u.Register(12345, 42)
+
+			if f.lastActor != "user:42" {
+				t.Errorf("Expected actor: %s, got %s", "user:42", f.lastActor)
+			}
 
 			if tc.expectedErr == nil {
 				if err != nil {
@@ -111,7 +148,11 @@ func TestUserServiceDelete(t *testing.T) {
 			}
 			u := NewUserService(f, log.New(os.Stdout, "", 0))
 
-			err := u.Delete(12345)
+			err := u.Delete(12345, 42)
+
+			if f.lastActor != "user:42" {
+				t.Errorf("Expected actor: %s, got %s", "user:42", f.lastActor)
+			}
 
 			if tc.expectedErr == nil {
 				if err != nil {
@@ -129,3 +170,187 @@ func TestUserServiceDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestUserServiceList(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoUsers   []models.User
+		repoErr     error
+		expected    []models.User
+		expectedErr error
+	}{
+		{
+			name:      "Success",
+			repoUsers: []models.User{{ID: 1, ChatID: 100}, {ID: 2, ChatID: 200}},
+			expected:  []models.User{{ID: 1, ChatID: 100}, {ID: 2, ChatID: 200}},
+		},
+		{
+			name:        "RepoError",
+			repoErr:     errors.New("db failed"),
+			expectedErr: domain.ErrGeneric,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &fakeUserRepo{users: tc.repoUsers, listErr: tc.repoErr}
+			u := NewUserService(f, log.New(os.Stdout, "", 0))
+
+			users, err := u.List()
+			active, activeErr := u.ListActive()
+
+			if activeErr != err || len(active) != len(users) {
+				t.Errorf("ListActive() = %+v, %v; want %+v, %v", active, activeErr, users, err)
+			}
+
+			if err != tc.expectedErr {
+				t.Fatalf("Expected error: %v, got %v", tc.expectedErr, err)
+			}
+			if len(users) != len(tc.expected) {
+				t.Fatalf("Expected %d users, got %d", len(tc.expected), len(users))
+			}
+			for i := range users {
+				if users[i] != tc.expected[i] {
+					t.Errorf("users[%d] = %+v; want %+v", i, users[i], tc.expected[i])
+				}
+			}
+		})
+	}
+}
+
+func TestUserServiceCount(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoCount   int
+		repoErr     error
+		expected    int
+		expectedErr error
+	}{
+		{
+			name:      "Success",
+			repoCount: 7,
+			expected:  7,
+		},
+		{
+			name:        "RepoError",
+			repoErr:     errors.New("db failed"),
+			expectedErr: domain.ErrGeneric,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &fakeUserRepo{count: tc.repoCount, countErr: tc.repoErr}
+			u := NewUserService(f, log.New(os.Stdout, "", 0))
+
+			count, err := u.Count()
+
+			if err != tc.expectedErr {
+				t.Fatalf("Expected error: %v, got %v", tc.expectedErr, err)
+			}
+			if count != tc.expected {
+				t.Errorf("Expected count: %d, got %d", tc.expected, count)
+			}
+		})
+	}
+}
+
+func TestUserServiceCountActive(t *testing.T) {
+	u := NewUserService(&fakeUserRepo{count: 7, activeCount: 5}, log.New(os.Stdout, "", 0))
+	if count, err := u.CountActive(); err != nil || count != 5 {
+		t.Errorf("CountActive = %d, %v; want 5, nil", count, err)
+	}
+
+	u = NewUserService(&fakeUserRepo{countErr: errors.New("db failed")}, log.New(os.Stdout, "", 0))
+	if _, err := u.CountActive(); err != domain.ErrGeneric {
+		t.Errorf("CountActive error = %v; want %v", err, domain.ErrGeneric)
+	}
+}
+
+func TestUserServiceGet(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoUser    *models.User
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			name:     "Success",
+			repoUser: &models.User{ID: 1, ChatID: 12345},
+		},
+		{
+			name:        "NotFound",
+			repoErr:     domain.ErrUserNotFound,
+			expectedErr: domain.ErrUserNotFound,
+		},
+		{
+			name:        "Other Error",
+			repoErr:     errors.New("other error"),
+			expectedErr: domain.ErrGeneric,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &fakeUserRepo{user: tc.repoUser, getErr: tc.repoErr}
+			u := NewUserService(f, log.New(os.Stdout, "", 0))
+
+			user, err := u.Get(12345)
+
+			if err != tc.expectedErr {
+				t.Fatalf("Expected error: %v, got %v", tc.expectedErr, err)
+			}
+			if user != tc.repoUser && tc.expectedErr == nil {
+				t.Errorf("Expected user %+v, got %+v", tc.repoUser, user)
+			}
+			if f.lastChatId != 12345 {
+				t.Errorf("Expected last chat id: %d, got %d", 12345, f.lastChatId)
+			}
+		})
+	}
+}
+
+func TestUserServiceDeactivate(t *testing.T) {
+	tests := []struct {
+		name        string
+		repoErr     error
+		expectedErr error
+	}{
+		{
+			name: "Success",
+		},
+		{
+			name:        "RepoError",
+			repoErr:     errors.New("db failed"),
+			expectedErr: domain.ErrGeneric,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := &fakeUserRepo{blockErr: tc.repoErr}
+			u := NewUserService(f, log.New(os.Stdout, "", 0))
+
+			err := u.Deactivate(12345, "blocked")
+
+			if err != tc.expectedErr {
+				t.Fatalf("Expected error: %v, got %v", tc.expectedErr, err)
+			}
+			if f.lastChatId != 12345 {
+				t.Errorf("Expected last chat id: %d, got %d", 12345, f.lastChatId)
+			}
+		})
+	}
+}
+
+func TestUserServiceRemove(t *testing.T) {
+	f := &fakeUserRepo{}
+	u := NewUserService(f, log.New(os.Stdout, "", 0))
+
+	if err := u.Remove(12345, "cli"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if f.lastChatId != 12345 || f.lastActor != "cli" {
+		t.Errorf("DeleteUser called with chat_id=%d actor=%q; want 12345 and cli", f.lastChatId, f.lastActor)
+	}
+}
@@ -0,0 +1,28 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log"
+
+	"github.com/pressly/goose/v3"
+)
+
+var gooseRun = goose.RunContext
+
+func Migrate(ctx context.Context, db *sql.DB, migrations fs.FS, logger *log.Logger, command string, args ...string) error {
+	goose.SetBaseFS(migrations)
+	goose.SetLogger(logger)
+
+	if err := goose.SetDialect("postgres"); err != nil {
+		return fmt.Errorf("Failed to set migration dialect: %w", err)
+	}
+
+	if err := gooseRun(ctx, command, db, ".", args...); err != nil {
+		return fmt.Errorf("Failed to run migration %q: %w", command, err)
+	}
+
+	return nil
+}
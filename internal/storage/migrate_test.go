@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMigrate(t *testing.T) {
+	origRun := gooseRun
+	defer func() { gooseRun = origRun }()
+
+	logger := log.New(os.Stdout, "", 0)
+	migrations := fstest.MapFS{"001_users.sql": &fstest.MapFile{Data: []byte("-- +goose Up\n")}}
+
+	t.Run("Success", func(t *testing.T) {
+		var gotCommand string
+		var gotArgs []string
+		gooseRun = func(ctx context.Context, command string, db *sql.DB, dir string, args ...string) error {
+			gotCommand = command
+			gotArgs = args
+			return nil
+		}
+
+		if err := Migrate(context.Background(), nil, migrations, logger, "up-to", "1"); err != nil {
+			t.Fatalf("expected no error, got: %v", err)
+		}
+		if gotCommand != "up-to" {
+			t.Errorf("command = %q; want %q", gotCommand, "up-to")
+		}
+		if len(gotArgs) != 1 || gotArgs[0] != "1" {
+			t.Errorf("args = %v; want [1]", gotArgs)
+		}
+	})
+
+	t.Run("Fail", func(t *testing.T) {
+		gooseRun = func(ctx context.Context, command string, db *sql.DB, dir string, args ...string) error {
+			return errors.New("ERROR")
+		}
+
+		err := Migrate(context.Background(), nil, migrations, logger, "up")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+		if !strings.Contains(err.Error(), "Failed to run migration") {
+			t.Errorf("error = %q; want it to contain %q", err.Error(), "Failed to run migration")
+		}
+	})
+}
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS roles (
+    chat_id BIGINT NOT NULL,
+    role TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (chat_id, role)
+);
+
+ALTER TABLE users ADD COLUMN blocked_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS message_stats (
+    day DATE PRIMARY KEY,
+    sent INTEGER NOT NULL DEFAULT 0
+);
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE message_stats;
+ALTER TABLE users DROP COLUMN blocked_at;
+DROP TABLE roles;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE chat_preferences ADD COLUMN IF NOT EXISTS live_message_id BIGINT;
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE chat_preferences DROP COLUMN IF EXISTS live_message_id;
+-- +goose StatementEnd
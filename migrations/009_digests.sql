@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS rate_history (
+    currency TEXT NOT NULL,
+    date DATE NOT NULL,
+    unit INT NOT NULL DEFAULT 1,
+    buying DOUBLE PRECISION NOT NULL,
+    selling DOUBLE PRECISION NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (currency, date)
+);
+
+ALTER TABLE chat_preferences ADD COLUMN IF NOT EXISTS digest TEXT;
+ALTER TABLE chat_preferences ADD COLUMN IF NOT EXISTS digest_sent_on DATE;
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE chat_preferences DROP COLUMN IF EXISTS digest_sent_on;
+ALTER TABLE chat_preferences DROP COLUMN IF EXISTS digest;
+DROP TABLE rate_history;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE rate_history
+    ALTER COLUMN buying TYPE NUMERIC(18, 6) USING round(buying::NUMERIC, 6),
+    ALTER COLUMN selling TYPE NUMERIC(18, 6) USING round(selling::NUMERIC, 6);
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rate_history
+    ALTER COLUMN buying TYPE DOUBLE PRECISION,
+    ALTER COLUMN selling TYPE DOUBLE PRECISION;
+-- +goose StatementEnd
@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE chat_preferences ADD COLUMN IF NOT EXISTS price_type TEXT;
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE chat_preferences DROP COLUMN IF EXISTS price_type;
+-- +goose StatementEnd
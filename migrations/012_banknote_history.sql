@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE rate_history ADD COLUMN IF NOT EXISTS banknote_buying NUMERIC(18, 6) NOT NULL DEFAULT 0;
+ALTER TABLE rate_history ADD COLUMN IF NOT EXISTS banknote_selling NUMERIC(18, 6) NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE rate_history DROP COLUMN IF EXISTS banknote_selling;
+ALTER TABLE rate_history DROP COLUMN IF EXISTS banknote_buying;
+-- +goose StatementEnd